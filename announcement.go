@@ -0,0 +1,159 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAnnouncementWorkers is the max number of courses that
+// an announcement will be posted to at the same time.
+const maxAnnouncementWorkers = 8
+
+// Announcement is a new announcement that can be posted
+// to many courses at once.
+type Announcement struct {
+	Title   string
+	Message string
+	// DelayedPostAt will schedule the announcement to be posted
+	// at a later time. The announcement is posted right away if
+	// this is zero.
+	DelayedPostAt time.Time
+	// LockAt will lock the announcement for comments at the given time.
+	LockAt time.Time
+
+	// Attachments are uploaded to the files of each course and
+	// linked at the bottom of the message.
+	Attachments []AnnouncementAttachment
+	// AttachmentFolder is the folder path that attachments will be
+	// uploaded to. Defaults to the root folder of the course.
+	AttachmentFolder string
+}
+
+// AnnouncementAttachment is a file to be attached to an announcement.
+// The contents are held in memory so they can be uploaded once for
+// each course.
+type AnnouncementAttachment struct {
+	Filename string
+	Data     []byte
+}
+
+// AnnouncementResult is the result of posting an
+// announcement to one context.
+type AnnouncementResult struct {
+	ContextCode string
+	Topic       *DiscussionTopic
+	Files       []*File
+	Err         error
+}
+
+// PostAnnouncement will post an announcement to each context code given.
+// Context codes should look like "course_1234". The results are returned
+// in the same order as the context codes, a failure to post to one
+// context does not stop the others.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics.create
+func (c *Canvas) PostAnnouncement(
+	contextCodes []string,
+	a *Announcement,
+	opts ...Option,
+) []AnnouncementResult {
+	return postAnnouncement(c.client, contextCodes, a, opts)
+}
+
+// PostAnnouncement will post an announcement to each context code given.
+func PostAnnouncement(
+	contextCodes []string,
+	a *Announcement,
+	opts ...Option,
+) []AnnouncementResult {
+	return ca.PostAnnouncement(contextCodes, a, opts...)
+}
+
+// PostAnnouncement will post an announcement to the course.
+func (c *Course) PostAnnouncement(a *Announcement, opts ...Option) (*DiscussionTopic, error) {
+	res := announce(c.client, c.ContextCode(), a, opts)
+	return res.Topic, res.Err
+}
+
+func postAnnouncement(d doer, codes []string, a *Announcement, opts []Option) []AnnouncementResult {
+	var (
+		wg      sync.WaitGroup
+		results = make([]AnnouncementResult, len(codes))
+		sem     = make(chan struct{}, maxAnnouncementWorkers)
+	)
+	for i, code := range codes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, code string) {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = announce(d, code, a, opts)
+		}(i, code)
+	}
+	wg.Wait()
+	return results
+}
+
+func announce(d doer, code string, a *Announcement, opts []Option) (res AnnouncementResult) {
+	res.ContextCode = code
+	ctxpath := pathFromContextCode(code)
+	if ctxpath == "" {
+		res.Err = fmt.Errorf("invalid context code %q", code)
+		return res
+	}
+	message := a.Message
+	if len(a.Attachments) > 0 {
+		links := make([]string, 0, len(a.Attachments))
+		for _, att := range a.Attachments {
+			var fileopts []Option
+			if a.AttachmentFolder != "" {
+				fileopts = append(fileopts, Opt("parent_folder_path", a.AttachmentFolder))
+			}
+			f, err := uploadFile(
+				d, bytes.NewReader(att.Data),
+				fmt.Sprintf("/%s/files", ctxpath),
+				newFileUploadParams(att.Filename, fileopts),
+			)
+			if err != nil {
+				res.Err = err
+				return res
+			}
+			res.Files = append(res.Files, f)
+			links = append(links, fmt.Sprintf(
+				`<a class="instructure_file_link" href="/%s/files/%d">%s</a>`,
+				ctxpath, f.ID, html.EscapeString(att.Filename),
+			))
+		}
+		message += "<p>" + strings.Join(links, "<br>") + "</p>"
+	}
+
+	q := params{
+		"title":           {a.Title},
+		"message":         {message},
+		"is_announcement": {"true"},
+	}
+	if !a.DelayedPostAt.IsZero() {
+		q.Set("delayed_post_at", a.DelayedPostAt.Format(time.RFC3339))
+	}
+	if !a.LockAt.IsZero() {
+		q.Set("lock_at", a.LockAt.Format(time.RFC3339))
+	}
+	q.Add(opts)
+	resp, err := post(d, fmt.Sprintf("/%s/discussion_topics", ctxpath), q)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+	res.Topic = &DiscussionTopic{path: ctxpath}
+	if res.Err = json.NewDecoder(resp.Body).Decode(res.Topic); res.Err != nil {
+		res.Topic = nil
+		return res
+	}
+	res.Topic.setclient(d)
+	return res
+}
@@ -0,0 +1,301 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// AppointmentGroup is a group of time slots that
+// users can sign up for using the scheduler.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html
+type AppointmentGroup struct {
+	ID              int       `json:"id" url:"-"`
+	Title           string    `json:"title" url:"title,omitempty"`
+	StartAt         time.Time `json:"start_at" url:"-"`
+	EndAt           time.Time `json:"end_at" url:"-"`
+	Description     string    `json:"description" url:"description,omitempty"`
+	LocationName    string    `json:"location_name" url:"location_name,omitempty"`
+	LocationAddress string    `json:"location_address" url:"location_address,omitempty"`
+	// ContextCodes are the courses the appointment group belongs to,
+	// ex. "course_1234".
+	ContextCodes []string `json:"context_codes" url:"context_codes,brackets,omitempty"`
+	// SubContextCodes are the sections or group categories that the
+	// appointment group is limited to, ex. "course_section_1234".
+	SubContextCodes               []string `json:"sub_context_codes" url:"sub_context_codes,brackets,omitempty"`
+	ParticipantsPerAppointment    int      `json:"participants_per_appointment" url:"participants_per_appointment,omitempty"`
+	MinAppointmentsPerParticipant int      `json:"min_appointments_per_participant" url:"min_appointments_per_participant,omitempty"`
+	MaxAppointmentsPerParticipant int      `json:"max_appointments_per_participant" url:"max_appointments_per_participant,omitempty"`
+	// ParticipantVisibility is either "private" or "protected"
+	ParticipantVisibility string `json:"participant_visibility" url:"participant_visibility,omitempty"`
+	// ParticipantType is either "User" or "Group"
+	ParticipantType   string `json:"participant_type" url:"-"`
+	ParticipantCount  int    `json:"participant_count" url:"-"`
+	RequiringAction   bool   `json:"requiring_action" url:"-"`
+	AppointmentsCount int    `json:"appointments_count" url:"-"`
+	// WorkflowState is one of "pending", "active" or "deleted"
+	WorkflowState string    `json:"workflow_state" url:"-"`
+	URL           string    `json:"url" url:"-"`
+	HTMLURL       string    `json:"html_url" url:"-"`
+	CreatedAt     time.Time `json:"created_at" url:"-"`
+	UpdatedAt     time.Time `json:"updated_at" url:"-"`
+	ReservedTimes []struct {
+		ID      int       `json:"id"`
+		StartAt time.Time `json:"start_at"`
+		EndAt   time.Time `json:"end_at"`
+	} `json:"reserved_times" url:"-"`
+
+	// Appointments are the time slots of the group. Only included
+	// when asking for IncludeOpt("appointments").
+	Appointments []*CalendarEvent `json:"appointments" url:"-"`
+
+	// Slots are new time slots to be added when creating
+	// or updating the appointment group.
+	Slots []AppointmentSlot `json:"-" url:"-"`
+
+	client doer
+}
+
+// AppointmentSlot is a time slot in an appointment group.
+type AppointmentSlot struct {
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+type appointmentGroupOptions struct {
+	AppointmentGroup `url:"appointment_group"`
+}
+
+// AppointmentGroups will list appointment groups. Use Opt("scope", "manageable")
+// to list the groups that can be managed instead of reserved.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.index
+func (c *Canvas) AppointmentGroups(opts ...Option) ([]*AppointmentGroup, error) {
+	return listAppointmentGroups(c.client, opts)
+}
+
+// AppointmentGroups will list appointment groups.
+func AppointmentGroups(opts ...Option) ([]*AppointmentGroup, error) {
+	return ca.AppointmentGroups(opts...)
+}
+
+// GetAppointmentGroup will get an appointment group by id.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.show
+func (c *Canvas) GetAppointmentGroup(id int, opts ...Option) (*AppointmentGroup, error) {
+	ag := &AppointmentGroup{client: c.client}
+	return ag, getjson(c.client, ag, optEnc(opts), "/appointment_groups/%d", id)
+}
+
+// GetAppointmentGroup will get an appointment group by id.
+func GetAppointmentGroup(id int, opts ...Option) (*AppointmentGroup, error) {
+	return ca.GetAppointmentGroup(id, opts...)
+}
+
+// CreateAppointmentGroup will create a new appointment group with the
+// time slots given in the group's Slots field. Pass Opt("publish", true)
+// to make the group available right away.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.create
+func (c *Canvas) CreateAppointmentGroup(ag AppointmentGroup, opts ...Option) (*AppointmentGroup, error) {
+	q, err := appointmentGroupQuery(&ag, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(c.client, "/appointment_groups", q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	res := &AppointmentGroup{client: c.client}
+	return res, json.NewDecoder(resp.Body).Decode(res)
+}
+
+// CreateAppointmentGroup will create a new appointment group.
+func CreateAppointmentGroup(ag AppointmentGroup, opts ...Option) (*AppointmentGroup, error) {
+	return ca.CreateAppointmentGroup(ag, opts...)
+}
+
+// Reserve will sign the current user up for an appointment time slot. The
+// reservation is returned as a new calendar event.
+//
+// https://canvas.instructure.com/doc/api/calendar_events.html#method.calendar_events_api.reserve
+func (c *Canvas) Reserve(slot *CalendarEvent, opts ...Option) (*CalendarEvent, error) {
+	return reserve(c.client, fmt.Sprintf("/calendar_events/%d/reservations", slot.ID), opts)
+}
+
+// Reserve will sign the current user up for an appointment time slot.
+func Reserve(slot *CalendarEvent, opts ...Option) (*CalendarEvent, error) {
+	return ca.Reserve(slot, opts...)
+}
+
+// ReserveFor will sign up a user or group for an appointment time slot. This
+// is used by teachers to make a reservation on behalf of a student.
+//
+// https://canvas.instructure.com/doc/api/calendar_events.html#method.calendar_events_api.reserve
+func (c *Canvas) ReserveFor(slot *CalendarEvent, participantID int, opts ...Option) (*CalendarEvent, error) {
+	return reserve(
+		c.client,
+		fmt.Sprintf("/calendar_events/%d/reservations/%d", slot.ID, participantID),
+		opts,
+	)
+}
+
+// ReserveFor will sign up a user or group for an appointment time slot.
+func ReserveFor(slot *CalendarEvent, participantID int, opts ...Option) (*CalendarEvent, error) {
+	return ca.ReserveFor(slot, participantID, opts...)
+}
+
+// CancelReservation will cancel a reservation given the reservation's
+// calendar event.
+//
+// https://canvas.instructure.com/doc/api/calendar_events.html#method.calendar_events_api.destroy
+func (c *Canvas) CancelReservation(reservation *CalendarEvent, reason string) error {
+	resp, err := delete(
+		c.client, fmt.Sprintf("/calendar_events/%d", reservation.ID),
+		params{"cancel_reason": {reason}},
+	)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// CancelReservation will cancel a reservation given the reservation's
+// calendar event.
+func CancelReservation(reservation *CalendarEvent, reason string) error {
+	return ca.CancelReservation(reservation, reason)
+}
+
+// Update will send the appointment group's fields along with any new
+// time slots to canvas.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.update
+func (ag *AppointmentGroup) Update(opts ...Option) error {
+	q, err := appointmentGroupQuery(ag, opts)
+	if err != nil {
+		return err
+	}
+	return ag.update(q)
+}
+
+// Publish will make the appointment group available for reservations.
+func (ag *AppointmentGroup) Publish() error {
+	return ag.update(params{"appointment_group[publish]": {"1"}})
+}
+
+// Delete will delete the appointment group and cancel
+// any reservations with the given reason.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.destroy
+func (ag *AppointmentGroup) Delete(reason string) error {
+	resp, err := delete(
+		ag.client, fmt.Sprintf("/appointment_groups/%d", ag.ID),
+		params{"cancel_reason": {reason}},
+	)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// AvailableSlots will get the time slots of the appointment group that
+// still have room for more participants.
+func (ag *AppointmentGroup) AvailableSlots() ([]*CalendarEvent, error) {
+	err := getjson(
+		ag.client, ag, params{"include[]": {"appointments", "child_events"}},
+		"/appointment_groups/%d", ag.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]*CalendarEvent, 0, len(ag.Appointments))
+	for _, a := range ag.Appointments {
+		// a zero participant limit means there is no limit
+		if a.ParticipantsPerAppointment == 0 || a.AvailableSlots > 0 {
+			slots = append(slots, a)
+		}
+	}
+	return slots, nil
+}
+
+// Participants will list the users that are eligible to sign up for
+// the appointment group. Use Opt("registration_status", "registered")
+// to only get users with a reservation.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.users
+func (ag *AppointmentGroup) Participants(opts ...Option) ([]*User, error) {
+	return listUsers(ag.client, fmt.Sprintf("/appointment_groups/%d/users", ag.ID), opts)
+}
+
+// GroupParticipants will list the groups that are eligible to sign up
+// for the appointment group.
+//
+// https://canvas.instructure.com/doc/api/appointment_groups.html#method.appointment_groups.groups
+func (ag *AppointmentGroup) GroupParticipants(opts ...Option) ([]*Group, error) {
+	return listGroups(ag.client, fmt.Sprintf("/appointment_groups/%d/groups", ag.ID), opts)
+}
+
+func (ag *AppointmentGroup) update(q encoder) error {
+	resp, err := put(ag.client, fmt.Sprintf("/appointment_groups/%d", ag.ID), q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(ag)
+}
+
+func appointmentGroupQuery(ag *AppointmentGroup, opts []Option) (params, error) {
+	q, err := query.Values(&appointmentGroupOptions{*ag})
+	if err != nil {
+		return nil, err
+	}
+	for i, slot := range ag.Slots {
+		key := fmt.Sprintf("appointment_group[new_appointments][%d][]", i)
+		q[key] = []string{
+			slot.StartAt.Format(time.RFC3339),
+			slot.EndAt.Format(time.RFC3339),
+		}
+	}
+	p := params(q)
+	p.Add(toPrefixedOpts("appointment_group", opts))
+	return p, nil
+}
+
+func reserve(d doer, path string, opts []Option) (*CalendarEvent, error) {
+	resp, err := post(d, path, optEnc(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	e := &CalendarEvent{}
+	return e, json.NewDecoder(resp.Body).Decode(e)
+}
+
+func listAppointmentGroups(d doer, opts []Option) (groups []*AppointmentGroup, err error) {
+	ch := make(chan *AppointmentGroup)
+	pager := newPaginatedList(d, "/appointment_groups", func(r io.Reader) error {
+		list := make([]*AppointmentGroup, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, ag := range list {
+			ag.client = d
+			ch <- ag
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case ag := <-ch:
+			groups = append(groups, ag)
+		case err := <-errs:
+			return groups, err
+		}
+	}
+}
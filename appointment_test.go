@@ -0,0 +1,77 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestAppointmentGroups(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	start := time.Date(2020, 9, 1, 9, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/api/v1/appointment_groups", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q["appointment_group[context_codes][]"], []string{"course_1"})
+		is.Equal(q.Get("appointment_group[title]"), "Office Hours")
+		is.Equal(q.Get("appointment_group[participants_per_appointment]"), "1")
+		is.Equal(q["appointment_group[new_appointments][1][]"], []string{
+			"2020-09-01T09:30:00Z", "2020-09-01T10:00:00Z",
+		})
+		is.Equal(q.Get("appointment_group[publish]"), "true")
+		w.Write([]byte(`{"id":5,"title":"Office Hours","workflow_state":"active","participants_per_appointment":1}`))
+	})
+	mux.HandleFunc("/api/v1/appointment_groups/5", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query()["include[]"], []string{"appointments", "child_events"})
+		w.Write([]byte(`{"id":5,"appointments":[
+			{"id":10,"appointment_group_id":5,"participants_per_appointment":1,"available_slots":0,"child_events":[{"id":12,"user":{"id":3}}]},
+			{"id":11,"appointment_group_id":5,"participants_per_appointment":1,"available_slots":1}]}`))
+	})
+	mux.HandleFunc("/api/v1/appointment_groups/5/users", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("registration_status"), "registered")
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":3,"name":"student"}]`))
+	})
+	mux.HandleFunc("/api/v1/calendar_events/11/reservations/3", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		w.Write([]byte(`{"id":13,"parent_event_id":11,"appointment_group_id":5}`))
+	})
+	mux.HandleFunc("/api/v1/calendar_events/13", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "DELETE")
+		is.Equal(r.URL.Query().Get("cancel_reason"), "sick")
+		w.Write([]byte(`{"id":13}`))
+	})
+
+	ag, err := CreateAppointmentGroup(AppointmentGroup{
+		Title:                      "Office Hours",
+		ContextCodes:               []string{"course_1"},
+		ParticipantsPerAppointment: 1,
+		Slots: []AppointmentSlot{
+			{StartAt: start, EndAt: start.Add(30 * time.Minute)},
+			{StartAt: start.Add(30 * time.Minute), EndAt: start.Add(time.Hour)},
+		},
+	}, Opt("publish", true))
+	is.NoErr(err)
+	is.Equal(ag.ID, 5)
+
+	slots, err := ag.AvailableSlots()
+	is.NoErr(err)
+	is.Equal(len(slots), 1)
+	is.Equal(slots[0].ID, 11)
+	is.Equal(ag.Appointments[0].ChildEvents[0].User.ID, 3)
+
+	users, err := ag.Participants(Opt("registration_status", "registered"))
+	is.NoErr(err)
+	is.Equal(len(users), 1)
+
+	res, err := ReserveFor(slots[0], users[0].ID)
+	is.NoErr(err)
+	is.Equal(res.ParentEventID, 11)
+	is.NoErr(CancelReservation(res, "sick"))
+}
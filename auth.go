@@ -53,7 +53,7 @@ func do(d doer, req *http.Request) (*http.Response, error) {
 
 	var e error
 	switch resp.StatusCode {
-	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
 		return resp, err
 	case http.StatusForbidden:
 		resp.Body.Close()
@@ -61,13 +61,17 @@ func do(d doer, req *http.Request) (*http.Response, error) {
 	case http.StatusUnprocessableEntity:
 		return nil, errs.Pair(resp.Body.Close(), errs.New(resp.Status))
 	case http.StatusNotFound, http.StatusUnauthorized:
-		e = &AuthError{}
+		e = &AuthError{code: resp.StatusCode}
 	case http.StatusBadRequest, http.StatusInternalServerError:
 		e = &Error{Status: resp.Status}
 	default:
 		e = &Error{Status: resp.Status}
 	}
-	return nil, errs.Chain(e, json.NewDecoder(resp.Body).Decode(&e), resp.Body.Close())
+	// the body only adds details to the error, a body that is not json
+	// should not hide the status code
+	json.NewDecoder(resp.Body).Decode(e)
+	resp.Body.Close()
+	return nil, e
 }
 
 func get(c doer, endpoint string, vals encoder) (*http.Response, error) {
@@ -140,13 +144,17 @@ type auth struct {
 }
 
 func (a *auth) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
 	req.Header.Set("User-Agent", DefaultUserAgent)
 	if req.URL.Host == "" {
 		// TODO: don't do this, it has caused my too much pain
 		req.Host = a.host
 		req.URL.Host = a.host
 	}
+	// file downloads and uploads get redirected to other hosts
+	// which should never see the token
+	if req.URL.Host == a.host {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+	}
 	return a.rt.RoundTrip(req)
 }
 
@@ -191,9 +199,14 @@ func (e *Error) Error() string {
 type AuthError struct {
 	Status string     `json:"status"`
 	Errors []errorMsg `json:"errors"`
+
+	code int // http status code
 }
 
 func (ae *AuthError) Error() string {
+	if ae.Status == "" && len(ae.Errors) == 0 {
+		return http.StatusText(ae.code)
+	}
 	if ae.Status == "" {
 		return checkErrors(ae.Errors)
 	}
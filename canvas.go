@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -38,6 +37,10 @@ var (
 	ca *Canvas
 )
 
+// defaultConcurrency is the number of requests made at the same
+// time when downloading, mirroring, or searching many files.
+const defaultConcurrency = 4
+
 func init() {
 	token := os.Getenv("CANVAS_TOKEN")
 	SetToken(token)
@@ -65,6 +68,14 @@ func WithHost(token, host string) *Canvas {
 	return &Canvas{&client{Client: c, host: host}}
 }
 
+// WithClient will create a canvas object that sends requests using
+// a copy of the given http client, ex. to use a custom transport.
+func WithClient(c *http.Client, token, host string) *Canvas {
+	cli := *c
+	authorize(&cli, token, host)
+	return &Canvas{&client{Client: cli, host: host}}
+}
+
 // Canvas is the main api entry point.
 type Canvas struct {
 	client doer
@@ -92,7 +103,7 @@ func Courses(opts ...Option) ([]*Course, error) { return ca.Courses(opts...) }
 //
 // https://canvas.instructure.com/doc/api/courses.html#method.courses.index
 func (c *Canvas) Courses(opts ...Option) ([]*Course, error) {
-	return getCourses(c.client, "/courses", optEnc(opts))
+	return getCourses(c.client, "/courses", withInclude(opts, "favorites"))
 }
 
 func getCourses(c doer, path string, opts optEnc) (crs []*Course, err error) {
@@ -104,7 +115,7 @@ func getCourses(c doer, path string, opts optEnc) (crs []*Course, err error) {
 				return err
 			}
 			for _, course := range list {
-				course.client = c
+				course.setclient(c)
 				course.errorHandler = ConcurrentErrorHandler
 				ch <- course
 			}
@@ -137,12 +148,12 @@ func (c *Canvas) CoursesChan(opts ...Option) <-chan *Course {
 				return err
 			}
 			for _, course := range list {
-				course.client = c.client
+				course.setclient(c.client)
 				course.errorHandler = ConcurrentErrorHandler
 				ch <- course
 			}
 			return nil
-		}, opts)
+		}, withInclude(opts, "favorites"))
 	go handleErrs(pager.start(), ch, ConcurrentErrorHandler)
 	return ch
 }
@@ -156,8 +167,12 @@ func GetCourse(id int, opts ...Option) (*Course, error) { return ca.GetCourse(id
 //
 // https://canvas.instructure.com/doc/api/courses.html#method.courses.show
 func (c *Canvas) GetCourse(id int, opts ...Option) (*Course, error) {
-	course := &Course{client: c.client, errorHandler: ConcurrentErrorHandler}
-	return course, getjson(c.client, &course, optEnc(opts), "/courses/%d", id)
+	course := &Course{errorHandler: ConcurrentErrorHandler}
+	q := params{}
+	q.Add(withInclude(opts, "favorites"))
+	err := getjson(c.client, course, q, "/courses/%d", id)
+	course.setclient(c.client)
+	return course, err
 }
 
 // GetUser will return a user object given that user's ID.
@@ -176,30 +191,6 @@ func (c *Canvas) CurrentUser(opts ...Option) (*User, error) {
 // CurrentUser get the currently logged in user.
 func CurrentUser(opts ...Option) (*User, error) { return ca.CurrentUser(opts...) }
 
-// Todos will get the current user's todo's.
-func (c *Canvas) Todos() ([]TODO, error) {
-	todos := make([]TODO, 0)
-	return todos, getjson(c.client, &todos, url.Values{"per_page": {"100"}}, "/users/self/todo")
-}
-
-// Todos will get the current user's todo's.
-func Todos() ([]TODO, error) { return ca.Todos() }
-
-// TODO is a to-do struct
-type TODO struct {
-	Type              string      `json:"type"`
-	Assignment        *Assignment `json:"assignment"`
-	Quiz              *Quiz       `json:"quiz"`
-	Ignore            string      `json:"ignore"`
-	IgnorePerminantly string      `json:"ignore_perminantly"`
-	HTMLURL           string      `json:"html_url"`
-	NeedsGradingCount int         `json:"needs_grading_count"`
-	ContextType       string      `json:"context_type"`
-	ContextID         int         `json:"context_id"`
-	CourseID          int         `json:"course_id"`
-	GroupID           interface{} `json:"group_id"`
-}
-
 // NewFile will make a new file object. This will not
 // send any data to canvas.
 func NewFile(filename string) *File { return ca.NewFile(filename) }
@@ -391,7 +382,7 @@ func (c *Canvas) Announcements(
 	ch := make(chan *DiscussionTopic)
 	pager := newPaginatedList(
 		c.client, "/announcements",
-		sendDiscussionTopicFunc(ch), opts)
+		sendDiscussionTopicFunc(c.client, "", ch), opts)
 	arr = make([]*DiscussionTopic, 0)
 	errs := pager.start()
 	for {
@@ -414,45 +405,58 @@ func Announcements(
 
 // DiscussionTopic is a discussion topic
 type DiscussionTopic struct {
-	ID                      int         `json:"id"`
-	Title                   string      `json:"title"`
-	Message                 string      `json:"message"`
-	HTMLURL                 string      `json:"html_url"`
-	PostedAt                time.Time   `json:"posted_at"`
-	LastReplyAt             time.Time   `json:"last_reply_at"`
-	RequireInitialPost      bool        `json:"require_initial_post"`
-	UserCanSeePosts         bool        `json:"user_can_see_posts"`
-	DiscussionSubentryCount int         `json:"discussion_subentry_count"`
-	ReadState               string      `json:"read_state"`
-	UnreadCount             int         `json:"unread_count"`
-	Subscribed              bool        `json:"subscribed"`
-	SubscriptionHold        string      `json:"subscription_hold"`
-	AssignmentID            interface{} `json:"assignment_id"`
-	DelayedPostAt           interface{} `json:"delayed_post_at"`
-	Published               bool        `json:"published"`
-	LockAt                  interface{} `json:"lock_at"`
-	Locked                  bool        `json:"locked"`
-	Pinned                  bool        `json:"pinned"`
-	LockedForUser           bool        `json:"locked_for_user"`
-	LockInfo                interface{} `json:"lock_info"`
-	LockExplanation         string      `json:"lock_explanation"`
-	UserName                string      `json:"user_name"`
-	TopicChildren           []int       `json:"topic_children"`
+	ID                      int       `json:"id" url:"-"`
+	Title                   string    `json:"title" url:"title,omitempty"`
+	Message                 string    `json:"message" url:"message,omitempty"`
+	HTMLURL                 string    `json:"html_url" url:"-"`
+	PostedAt                time.Time `json:"posted_at" url:"-"`
+	LastReplyAt             time.Time `json:"last_reply_at" url:"-"`
+	RequireInitialPost      bool      `json:"require_initial_post" url:"require_initial_post,omitempty"`
+	UserCanSeePosts         bool      `json:"user_can_see_posts" url:"-"`
+	DiscussionSubentryCount int       `json:"discussion_subentry_count" url:"-"`
+	ReadState               string    `json:"read_state" url:"-"`
+	UnreadCount             int       `json:"unread_count" url:"-"`
+	Subscribed              bool      `json:"subscribed" url:"-"`
+	SubscriptionHold        string    `json:"subscription_hold" url:"-"`
+	AssignmentID            int       `json:"assignment_id" url:"-"`
+	DelayedPostAt           time.Time `json:"delayed_post_at" url:"delayed_post_at,omitempty"`
+	Published               bool      `json:"published" url:"published,omitempty"`
+	LockAt                  time.Time `json:"lock_at" url:"lock_at,omitempty"`
+	Locked                  bool      `json:"locked" url:"-"`
+	Pinned                  bool      `json:"pinned" url:"pinned,omitempty"`
+	LockedForUser           bool      `json:"locked_for_user" url:"-"`
+	LockInfo                *LockInfo `json:"lock_info" url:"-"`
+	LockExplanation         string    `json:"lock_explanation" url:"-"`
+	UserName                string    `json:"user_name" url:"-"`
+	TopicChildren           []int     `json:"topic_children" url:"-"`
 	GroupTopicChildren      []struct {
 		ID      int `json:"id"`
 		GroupID int `json:"group_id"`
-	} `json:"group_topic_children"`
-	RootTopicID     interface{} `json:"root_topic_id"`
-	PodcastURL      string      `json:"podcast_url"`
-	DiscussionType  string      `json:"discussion_type"`
-	GroupCategoryID interface{} `json:"group_category_id"`
-	Attachments     interface{} `json:"attachments"`
+	} `json:"group_topic_children" url:"-"`
+	RootTopicID    int    `json:"root_topic_id" url:"-"`
+	PodcastURL     string `json:"podcast_url" url:"-"`
+	ContextCode    string `json:"context_code" url:"-"`
+	IsAnnouncement bool   `json:"is_announcement" url:"is_announcement,omitempty"`
+	// DiscussionType can be either "side_comment" or "threaded"
+	DiscussionType  string  `json:"discussion_type" url:"discussion_type,omitempty"`
+	GroupCategoryID int     `json:"group_category_id" url:"group_category_id,omitempty"`
+	Attachments     []*File `json:"attachments" url:"-"`
 	Permissions     struct {
 		Attach bool `json:"attach"`
-	} `json:"permissions"`
-	AllowRating        bool `json:"allow_rating"`
-	OnlyGradersCanRate bool `json:"only_graders_can_rate"`
-	SortByRating       bool `json:"sort_by_rating"`
+	} `json:"permissions" url:"-"`
+	AllowRating        bool `json:"allow_rating" url:"allow_rating,omitempty"`
+	OnlyGradersCanRate bool `json:"only_graders_can_rate" url:"only_graders_can_rate,omitempty"`
+	SortByRating       bool `json:"sort_by_rating" url:"sort_by_rating,omitempty"`
+
+	client doer
+	path   string // context path, ex. "courses/1234"
+}
+
+func (dt *DiscussionTopic) setclient(d doer) {
+	dt.client = d
+	for _, f := range dt.Attachments {
+		f.setclient(d)
+	}
 }
 
 // CalendarEvents makes a call to get calendar events.
@@ -490,25 +494,40 @@ type calendarEventOptions struct {
 }
 
 // CreateCalendarEvent will send a calendar event to canvas to be created.
+// Set the event's Duplicate field to create a recurring event and the
+// ChildEventData field to give each section its own times. The
+// duplicates of a recurring event are returned in the Duplicates field.
 // https://canvas.instructure.com/doc/api/all_resources.html#method.calendar_events_api.create
 func (c *Canvas) CreateCalendarEvent(event *CalendarEvent) (*CalendarEvent, error) {
-	// TODO: figure out how to send theses fields:
-	// 	- calendar_event[child_event_data][X][start_at]
-	// 	- calendar_event[duplicate][count]
-	// 	- calendar_event[duplicate][interval]
-	// 	- calendar_event[duplicate][frequency]
-	// see https://canvas.instructure.com/doc/api/all_resources.html#method.calendar_events_api.create
 	q, err := query.Values(&calendarEventOptions{*event})
 	if err != nil {
 		return nil, err
 	}
+	for i, child := range event.ChildEventData {
+		prefix := fmt.Sprintf("calendar_event[child_event_data][%d]", i)
+		q.Set(prefix+"[start_at]", child.StartAt.Format(time.RFC3339))
+		q.Set(prefix+"[end_at]", child.EndAt.Format(time.RFC3339))
+		q.Set(prefix+"[context_code]", child.ContextCode)
+	}
 	resp, err := post(c.client, "/calendar_events", q)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	cal := &CalendarEvent{}
-	return cal, json.NewDecoder(resp.Body).Decode(cal)
+	res := struct {
+		*CalendarEvent
+		Duplicates []struct {
+			Event *CalendarEvent `json:"calendar_event"`
+		} `json:"duplicates"`
+	}{CalendarEvent: cal}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	for _, dup := range res.Duplicates {
+		cal.Duplicates = append(cal.Duplicates, dup.Event)
+	}
+	return cal, nil
 }
 
 // CreateCalendarEvent will send a calendar event to canvas to be created.
@@ -572,67 +591,78 @@ func DeleteCalendarEvent(e *CalendarEvent) (*CalendarEvent, error) {
 
 // CalendarEvent is a calendar event
 type CalendarEvent struct {
-	ID                         int         `json:"id" url:"-"`
-	Title                      string      `json:"title" url:"title,omitempty"`
-	ContextCode                string      `json:"context_code" url:"context_code,omitempty"`
-	StartAt                    time.Time   `json:"start_at" url:"start_at,omitempty"`
-	EndAt                      time.Time   `json:"end_at" url:"end_at,omitempty"`
-	CreatedAt                  time.Time   `json:"created_at" url:"-"`
-	UpdatedAt                  time.Time   `json:"updated_at" url:"-"`
-	Description                string      `json:"description" url:"description,omitempty"`
-	LocationName               string      `json:"location_name" url:"location_name,omitempty"`
-	LocationAddress            string      `json:"location_address" url:"location_address,omitempty"`
-	EffectiveContextCode       interface{} `json:"effective_context_code" url:"effective_context_code,omitempty"`
-	AllDay                     bool        `json:"all_day" url:"all_day,omitempty"`
-	AllContextCodes            string      `json:"all_context_codes" url:"-"`
-	WorkflowState              string      `json:"workflow_state" url:"-"`
-	Hidden                     bool        `json:"hidden" url:"-"`
-	ParentEventID              interface{} `json:"parent_event_id" url:"-"`
-	ChildEventsCount           int         `json:"child_events_count" url:"-"`
-	ChildEvents                interface{} `json:"child_events" url:"-"`
-	URL                        string      `json:"url" url:"-"`
-	HTMLURL                    string      `json:"html_url" url:"-"`
-	AllDayDate                 string      `json:"all_day_date" url:"-"`
-	AppointmentGroupID         interface{} `json:"appointment_group_id" url:"-"`
-	AppointmentGroupURL        string      `json:"appointment_group_url" url:"-"`
-	OwnReservation             bool        `json:"own_reservation" url:"-"`
-	ReserveURL                 string      `json:"reserve_url" url:"-"`
-	Reserved                   bool        `json:"reserved" url:"-"`
-	ParticipantType            string      `json:"participant_type" url:"-"`
-	ParticipantsPerAppointment interface{} `json:"participants_per_appointment" url:"-"`
-	AvailableSlots             interface{} `json:"available_slots" url:"-"`
-	User                       *User       `json:"user" url:"-"`
-	Group                      interface{} `json:"group" url:"-"`
-}
-
-// Conversations returns a list of conversations
-func (c *Canvas) Conversations(opts ...Option) (conversations []Conversation, err error) {
-	return conversations, getjson(c.client, &conversations, optEnc(opts), "/conversations")
-}
-
-// Conversations returns a list of conversations
-func Conversations(opts ...Option) ([]Conversation, error) {
-	return ca.Conversations(opts...)
-}
-
-// Conversation is a conversation.
-type Conversation struct {
-	ID               int         `json:"id"`
-	Subject          string      `json:"subject"`
-	WorkflowState    string      `json:"workflow_state"`
-	LastMessage      string      `json:"last_message"`
-	StartAt          time.Time   `json:"start_at"`
-	MessageCount     int         `json:"message_count"`
-	Subscribed       bool        `json:"subscribed"`
-	Private          bool        `json:"private"`
-	Starred          bool        `json:"starred"`
-	Properties       interface{} `json:"properties"`
-	Audience         interface{} `json:"audience"`
-	AudienceContexts interface{} `json:"audience_contexts"`
-	AvatarURL        string      `json:"avatar_url"`
-	Participants     interface{} `json:"participants"`
-	Visible          bool        `json:"visible"`
-	ContextName      string      `json:"context_name"`
+	ID                         int              `json:"id" url:"-"`
+	Title                      string           `json:"title" url:"title,omitempty"`
+	ContextCode                string           `json:"context_code" url:"context_code,omitempty"`
+	StartAt                    time.Time        `json:"start_at" url:"start_at,omitempty"`
+	EndAt                      time.Time        `json:"end_at" url:"end_at,omitempty"`
+	CreatedAt                  time.Time        `json:"created_at" url:"-"`
+	UpdatedAt                  time.Time        `json:"updated_at" url:"-"`
+	Description                string           `json:"description" url:"description,omitempty"`
+	LocationName               string           `json:"location_name" url:"location_name,omitempty"`
+	LocationAddress            string           `json:"location_address" url:"location_address,omitempty"`
+	EffectiveContextCode       string           `json:"effective_context_code" url:"effective_context_code,omitempty"`
+	AllDay                     bool             `json:"all_day" url:"all_day,omitempty"`
+	AllContextCodes            string           `json:"all_context_codes" url:"-"`
+	WorkflowState              string           `json:"workflow_state" url:"-"`
+	Hidden                     bool             `json:"hidden" url:"-"`
+	ParentEventID              int              `json:"parent_event_id" url:"-"`
+	ChildEventsCount           int              `json:"child_events_count" url:"-"`
+	ChildEvents                []*CalendarEvent `json:"child_events" url:"-"`
+	URL                        string           `json:"url" url:"-"`
+	HTMLURL                    string           `json:"html_url" url:"-"`
+	AllDayDate                 string           `json:"all_day_date" url:"-"`
+	AppointmentGroupID         int              `json:"appointment_group_id" url:"-"`
+	AppointmentGroupURL        string           `json:"appointment_group_url" url:"-"`
+	OwnReservation             bool             `json:"own_reservation" url:"-"`
+	ReserveURL                 string           `json:"reserve_url" url:"-"`
+	Reserved                   bool             `json:"reserved" url:"-"`
+	ParticipantType            string           `json:"participant_type" url:"-"`
+	ParticipantsPerAppointment int              `json:"participants_per_appointment" url:"-"`
+	AvailableSlots             int              `json:"available_slots" url:"-"`
+	User                       *User            `json:"user" url:"-"`
+	Group                      *Group           `json:"group" url:"-"`
+
+	// ChildEventData is only used when creating an event and
+	// will give sections different times for the same event.
+	ChildEventData []ChildEventData `json:"-" url:"-"`
+	// Duplicate is only used when creating an event and will
+	// create a series of recurring events.
+	Duplicate *EventDuplicate `json:"-" url:"duplicate,omitempty"`
+	// Duplicates holds the rest of a series of recurring events
+	// after creating an event with Duplicate set.
+	Duplicates []*CalendarEvent `json:"-" url:"-"`
+}
+
+// ChildEventData holds the times of a section-specific
+// child event.
+type ChildEventData struct {
+	StartAt time.Time
+	EndAt   time.Time
+	// ContextCode is the section's context code,
+	// ex. "course_section_1234"
+	ContextCode string
+}
+
+// Event frequencies for recurring events.
+const (
+	RepeatDaily   = "daily"
+	RepeatWeekly  = "weekly"
+	RepeatMonthly = "monthly"
+)
+
+// EventDuplicate describes how an event should be repeated.
+type EventDuplicate struct {
+	// Count is the number of times to copy the event.
+	Count int `url:"count"`
+	// Interval is the number of Frequency units between each copy,
+	// ex. an Interval of 2 with a Weekly Frequency is every other week.
+	Interval int `url:"interval,omitempty"`
+	// Frequency is either RepeatDaily, RepeatWeekly or RepeatMonthly
+	Frequency string `url:"frequency"`
+	// AppendIterator will add the event's number in
+	// the series to the title of each event.
+	AppendIterator bool `url:"append_iterator,omitempty"`
 }
 
 // Bookmarks will get the current user's bookmarks.
@@ -673,10 +703,11 @@ type Bookmark struct {
 // pathVar is an interface{} because internally, either "self" or some integer id
 // will be passed to be used as an api path parameter.
 func getUser(c doer, pathVar interface{}, opts []Option) (u *User, err error) {
-	u = &User{client: c}
+	u = &User{}
 	if err = getjson(c, u, optEnc(opts), "users/%v", pathVar); err != nil {
 		return nil, err
 	}
+	u.setclient(c)
 	return u, nil
 }
 
@@ -711,14 +742,19 @@ func getAccounts(d doer, path string, opts []Option) (accts []Account, err error
 	return
 }
 
-func sendDiscussionTopicFunc(ch chan *DiscussionTopic) sendFunc {
+func sendDiscussionTopicFunc(d doer, ctxpath string, ch chan *DiscussionTopic) sendFunc {
 	return func(r io.Reader) error {
 		discs := make([]*DiscussionTopic, 0)
 		if err := json.NewDecoder(r).Decode(&discs); err != nil {
 			return err
 		}
-		for _, d := range discs {
-			ch <- d
+		for _, disc := range discs {
+			disc.setclient(d)
+			disc.path = ctxpath
+			if disc.path == "" {
+				disc.path = pathFromContextCode(disc.ContextCode)
+			}
+			ch <- disc
 		}
 		return nil
 	}
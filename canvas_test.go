@@ -211,6 +211,41 @@ func TestUpdateCalendarEvent(t *testing.T) {
 	DeleteCalendarEventByID(event.ID)
 }
 
+func TestCreateRecurringCalendarEvent(t *testing.T) {
+	is := is.New(t)
+	cli, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: cli})()
+	start := time.Date(2020, 9, 1, 14, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/api/v1/calendar_events", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q.Get("calendar_event[duplicate][count]"), "3")
+		is.Equal(q.Get("calendar_event[duplicate][frequency]"), "weekly")
+		is.Equal(q.Get("calendar_event[duplicate][interval]"), "")
+		is.Equal(q.Get("calendar_event[child_event_data][0][start_at]"), "2020-09-01T14:00:00Z")
+		is.Equal(q.Get("calendar_event[child_event_data][1][context_code]"), "course_section_2")
+		w.Write([]byte(`{"id":1,"title":"Lab","child_events_count":2,
+			"child_events":[{"id":2,"parent_event_id":1},{"id":3,"parent_event_id":1}],
+			"duplicates":[{"calendar_event":{"id":4}},{"calendar_event":{"id":5}},{"calendar_event":{"id":6}}]}`))
+	})
+	event, err := CreateCalendarEvent(&CalendarEvent{
+		Title:       "Lab",
+		ContextCode: "course_1",
+		Duplicate:   &EventDuplicate{Count: 3, Frequency: RepeatWeekly},
+		ChildEventData: []ChildEventData{
+			{StartAt: start, EndAt: start.Add(time.Hour), ContextCode: "course_section_1"},
+			{StartAt: start.Add(2 * time.Hour), EndAt: start.Add(3 * time.Hour), ContextCode: "course_section_2"},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(event.ID, 1)
+	is.Equal(len(event.ChildEvents), 2)
+	is.Equal(event.ChildEvents[1].ParentEventID, 1)
+	is.Equal(len(event.Duplicates), 3)
+	is.Equal(event.Duplicates[2].ID, 6)
+}
+
 func TestUser_Err(t *testing.T) {
 	is := is.New(t)
 	u, err := testUser()
@@ -443,6 +478,134 @@ func TestCourse_User(t *testing.T) {
 	}
 }
 
+func TestCourse_Enrollments(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1234/enrollments", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.Method {
+		case "GET":
+			is.Equal(q["type[]"], []string{"TeacherEnrollment", "StudentEnrollment"})
+			is.Equal(q.Get("state[]"), "active")
+			w.Header().Set("Link", `<https://canvas.instructure.com/api/v1/courses/1234/enrollments?page=1&per_page=10>; rel="current",<https://canvas.instructure.com/api/v1/courses/1234/enrollments?page=1&per_page=10>; rel="first",<https://canvas.instructure.com/api/v1/courses/1234/enrollments?page=1&per_page=10>; rel="last"`)
+			w.Write([]byte(`[{"id":1,"course_id":1234,"type":"TeacherEnrollment"},{"id":2,"course_id":1234,"type":"StudentEnrollment"}]`))
+		case "POST":
+			is.Equal(q.Get("enrollment[user_id]"), "2")
+			is.Equal(q.Get("enrollment[type]"), "StudentEnrollment")
+			is.Equal(q.Get("enrollment[course_section_id]"), "")
+			is.Equal(q.Get("enrollment[enrollment_state]"), "active")
+			is.Equal(q.Get("enrollment[notify]"), "true")
+			w.Write([]byte(`{"id":3,"course_id":1234,"user_id":2,"type":"StudentEnrollment","enrollment_state":"active"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/courses/1234/enrollments/3", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "DELETE")
+		is.Equal(r.URL.Query().Get("task"), "conclude")
+		w.Write([]byte(`{"id":3,"course_id":1234,"enrollment_state":"completed"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1234/enrollments/3/reject", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		w.Write([]byte(`{"success":false}`))
+	})
+	course := &Course{client: client, ID: 1234}
+	enrollments, err := course.ListEnrollments(OptTeacher, OptStudent, ArrayOpt("state", "active"))
+	is.NoErr(err)
+	is.Equal(len(enrollments), 2)
+	for _, e := range enrollments {
+		is.True(e.client != nil)
+	}
+
+	e, err := course.Enroll(2, StudentEnrollment, 0, "active", Opt("notify", true))
+	is.NoErr(err)
+	is.Equal(e.ID, 3)
+	is.NoErr(e.Conclude())
+	is.Equal(e.EnrollmentState, "completed")
+	is.True(e.Reject() != nil)
+
+	// enrollments that are part of other objects need a client too
+	mux.HandleFunc("/api/v1/courses/1234", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1234,"enrollments":[{"id":3,"course_id":1234,"type":"student"}]}`))
+	})
+	mux.HandleFunc("/api/v1/users/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":2,"enrollments":[{"id":3,"course_id":1234,"user_id":2}]}`))
+	})
+	c := &Canvas{client: client}
+	course, err = c.GetCourse(1234)
+	is.NoErr(err)
+	is.Equal(len(course.Enrollments), 1)
+	is.NoErr(course.Enrollments[0].Conclude())
+	user, err := c.GetUser(2)
+	is.NoErr(err)
+	is.Equal(len(user.Enrollments), 1)
+	is.NoErr(user.Enrollments[0].Conclude())
+}
+
+func TestFavorites(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+	mux.HandleFunc("/api/v1/users/self/favorites/courses", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Header().Set("Link", `<https://canvas.instructure.com/api/v1/users/self/favorites/courses?page=1&per_page=10>; rel="current",<https://canvas.instructure.com/api/v1/users/self/favorites/courses?page=1&per_page=10>; rel="first",<https://canvas.instructure.com/api/v1/users/self/favorites/courses?page=1&per_page=10>; rel="last"`)
+			w.Write([]byte(`[{"id":1234}]`))
+		case "DELETE":
+			w.Write([]byte(`{"message":"OK"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/users/self/favorites/courses/1234", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"context_id":1234,"context_type":"course"}`))
+	})
+	courses, err := FavoriteCourses()
+	is.NoErr(err)
+	is.Equal(len(courses), 1)
+	is.True(courses[0].IsFavorite)
+
+	course := courses[0]
+	is.NoErr(course.Unfavorite())
+	is.True(!course.IsFavorite)
+	fav, err := AddFavoriteCourse(course.ID)
+	is.NoErr(err)
+	is.Equal(fav.ContextID, 1234)
+	is.NoErr(ResetFavoriteCourses())
+
+	mux.HandleFunc("/api/v1/courses", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query()["include[]"], []string{"favorites", "term"})
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":1234,"is_favorite":true},{"id":1235,"is_favorite":false}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1234", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query()["include[]"], []string{"favorites", "term"})
+		w.Write([]byte(`{"id":1234,"is_favorite":true}`))
+	})
+	courses, err = Courses(IncludeOpt("term"))
+	is.NoErr(err)
+	is.Equal(len(courses), 2)
+	is.True(courses[0].IsFavorite)
+	is.True(!courses[1].IsFavorite)
+	n := 0
+	for c := range CoursesChan(IncludeOpt("term")) {
+		is.Equal(c.IsFavorite, c.ID == 1234)
+		n++
+	}
+	is.Equal(n, 2)
+	course, err = GetCourse(1234, IncludeOpt("term"))
+	is.NoErr(err)
+	is.True(course.IsFavorite)
+
+	mux.HandleFunc("/api/v1/users/5/courses", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query()["include[]"], []string{"favorites", "term"})
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":1234,"is_favorite":true}]`))
+	})
+	courses, err = (&User{ID: 5, client: client}).Courses(IncludeOpt("term"))
+	is.NoErr(err)
+	is.Equal(len(courses), 1)
+	is.True(courses[0].IsFavorite)
+}
+
 func TestCourse_DiscussionTopics(t *testing.T) {
 	t.Skip("this test is usless")
 	c := testCourse()
@@ -512,6 +675,23 @@ func TestErrors(t *testing.T) {
 	is.Equal(err.Error(), "error status: this is an error; sentryId: testid")
 }
 
+func TestNoContent(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// a 204 is a successful response with an empty body
+	resp, err := do(client, newreq("DELETE", "/courses/1/discussion_topics/2", nil))
+	is.NoErr(err)
+	is.Equal(resp.StatusCode, http.StatusNoContent)
+	is.NoErr(resp.Body.Close())
+	topic := &DiscussionTopic{ID: 2, client: client, path: "courses/1"}
+	is.NoErr(topic.Delete())
+}
+
 func TestRateLimitErr(t *testing.T) {
 	cli, mux, server := testServer()
 	defer server.Close()
@@ -554,6 +734,7 @@ func TestUtils(t *testing.T) {
 	}{
 		{"Course", "courses"},
 		{"User", "users"},
+		{"Group", "groups"},
 		{"GroupCategory", "group_categories"},
 		{"Account", "accounts"},
 	}
@@ -0,0 +1,354 @@
+package canvastest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	canvas "github.com/ArchWizard56/go-canvas"
+)
+
+func (s *Server) userRoutes() {
+	s.handle("GET", "users/:user", s.getUser)
+	s.handle("GET", "users/:user/profile", s.getUser)
+}
+
+func (s *Server) courseRoutes() {
+	s.handle("GET", "courses", s.listCourses)
+	s.handle("GET", "courses/:course", s.getCourse)
+	s.handle("GET", "courses/:course/users", s.listCourseUsers)
+	s.handle("GET", "courses/:course/users/:user", s.getCourseUser)
+	s.handle("GET", "courses/:course/assignments", s.listAssignments)
+	s.handle("POST", "courses/:course/assignments", s.createAssignmentHandler)
+	s.handle("GET", "courses/:course/assignments/:assignment", s.getAssignment)
+	s.handle("PUT", "courses/:course/assignments/:assignment", s.updateAssignment)
+	s.handle("DELETE", "courses/:course/assignments/:assignment", s.deleteAssignment)
+	s.handle("GET", "courses/:course/assignments/:assignment/submissions", s.listSubmissions)
+	s.handle("POST", "courses/:course/assignments/:assignment/submissions", s.submit)
+	s.handle("GET", "courses/:course/assignments/:assignment/submissions/:user", s.getSubmission)
+	s.handle("PUT", "courses/:course/assignments/:assignment/submissions/:user", s.gradeSubmission)
+}
+
+func (s *Server) userID(id string) int {
+	if id == "self" {
+		return s.self
+	}
+	n, _ := strconv.Atoi(id)
+	return n
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	u, ok := s.users[s.userID(vars["user"])]
+	if !ok {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+func (s *Server) course(w http.ResponseWriter, vars map[string]string) *course {
+	id, _ := strconv.Atoi(vars["course"])
+	c, ok := s.courses[id]
+	if !ok || c.enrollments[s.self] == "" {
+		notFound(w)
+		return nil
+	}
+	return c
+}
+
+func (s *Server) listCourses(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var ids []int
+	for id, c := range s.courses {
+		if c.enrollments[s.self] == "" {
+			continue
+		}
+		if state := r.FormValue("enrollment_state"); state != "" && state != "active" {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	items := make([]interface{}, len(ids))
+	for i, id := range ids {
+		items[i] = s.courseJSON(s.courses[id])
+	}
+	s.paginate(w, r, items)
+}
+
+func (s *Server) getCourse(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if c := s.course(w, vars); c != nil {
+		writeJSON(w, http.StatusOK, s.courseJSON(c))
+	}
+}
+
+func (s *Server) courseJSON(c *course) canvas.Course {
+	res := c.Course
+	res.Enrollments = []*canvas.Enrollment{s.enrollment(c, s.self)}
+	for _, t := range c.enrollments {
+		if t == StudentEnrollment {
+			res.TotalStudents++
+		}
+	}
+	return res
+}
+
+func (s *Server) enrollment(c *course, userID int) *canvas.Enrollment {
+	t := c.enrollments[userID]
+	return &canvas.Enrollment{
+		CourseID:        c.ID,
+		UserID:          userID,
+		Type:            strings.ToLower(strings.TrimSuffix(t, "Enrollment")),
+		Role:            t,
+		EnrollmentState: "active",
+	}
+}
+
+func (s *Server) listCourseUsers(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	c := s.course(w, vars)
+	if c == nil {
+		return
+	}
+	types := append(r.Form["enrollment_type[]"], r.Form["enrollment_type"]...)
+	term := strings.ToLower(r.FormValue("search_term"))
+	var ids []int
+	for id, t := range c.enrollments {
+		if len(types) > 0 && !containsFold(types, strings.TrimSuffix(t, "Enrollment")) {
+			continue
+		}
+		if term != "" && !strings.Contains(strings.ToLower(s.users[id].Name), term) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	items := make([]interface{}, len(ids))
+	for i, id := range ids {
+		u := *s.users[id]
+		u.Enrollments = []*canvas.Enrollment{s.enrollment(c, id)}
+		items[i] = u
+	}
+	s.paginate(w, r, items)
+}
+
+func (s *Server) getCourseUser(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	c := s.course(w, vars)
+	if c == nil {
+		return
+	}
+	id := s.userID(vars["user"])
+	if c.enrollments[id] == "" {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.users[id])
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) assignment(w http.ResponseWriter, vars map[string]string) (*course, *canvas.Assignment) {
+	c := s.course(w, vars)
+	if c == nil {
+		return nil, nil
+	}
+	id, _ := strconv.Atoi(vars["assignment"])
+	a, ok := s.assignments[id]
+	if !ok || a.CourseID != c.ID {
+		notFound(w)
+		return nil, nil
+	}
+	return c, a
+}
+
+func (s *Server) listAssignments(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	c := s.course(w, vars)
+	if c == nil {
+		return
+	}
+	var list []*canvas.Assignment
+	for _, a := range s.assignments {
+		if a.CourseID == c.ID {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	items := make([]interface{}, len(list))
+	for i, a := range list {
+		items[i] = a
+	}
+	s.paginate(w, r, items)
+}
+
+func (s *Server) getAssignment(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if _, a := s.assignment(w, vars); a != nil {
+		writeJSON(w, http.StatusOK, a)
+	}
+}
+
+func (s *Server) createAssignmentHandler(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	c := s.course(w, vars)
+	if c == nil {
+		return
+	}
+	a := canvas.Assignment{}
+	setAssignmentFields(&a, r)
+	if a.Name == "" {
+		a.Name = "Unnamed Assignment"
+	}
+	writeJSON(w, http.StatusCreated, s.createAssignment(c.ID, a))
+}
+
+func (s *Server) updateAssignment(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	setAssignmentFields(a, r)
+	a.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (s *Server) deleteAssignment(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	delete(s.assignments, a.ID)
+	for key := range s.submissions {
+		if key.assignment == a.ID {
+			delete(s.submissions, key)
+		}
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func setAssignmentFields(a *canvas.Assignment, r *http.Request) {
+	if v, ok := r.Form["assignment[name]"]; ok {
+		a.Name = v[0]
+	}
+	if v, ok := r.Form["assignment[description]"]; ok {
+		a.Description = v[0]
+	}
+	if v, ok := r.Form["assignment[points_possible]"]; ok {
+		a.PointsPossible, _ = strconv.ParseFloat(v[0], 64)
+	}
+	if v, ok := r.Form["assignment[grading_type]"]; ok {
+		a.GradingType = canvas.GradingType(v[0])
+	}
+	if v, ok := r.Form["assignment[submission_types][]"]; ok {
+		a.SubmissionTypes = v
+	} else if v, ok = r.Form["assignment[submission_types]"]; ok {
+		a.SubmissionTypes = strings.Split(v[0], ",")
+	}
+	setTime(r, "assignment[due_at]", &a.DueAt)
+	if published, ok := formBool(r, "assignment[published]"); ok {
+		a.Published = published
+	}
+}
+
+func (s *Server) listSubmissions(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	var list []*canvas.Submission
+	for key, sub := range s.submissions {
+		if key.assignment == a.ID {
+			list = append(list, sub)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+	items := make([]interface{}, len(list))
+	for i, sub := range list {
+		items[i] = sub
+	}
+	s.paginate(w, r, items)
+}
+
+func (s *Server) getSubmission(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	sub, ok := s.submissions[submissionKey{a.ID, s.userID(vars["user"])}]
+	if !ok {
+		// canvas has a submission for every student even before they submit
+		sub = &canvas.Submission{
+			AssignmentID:  a.ID,
+			UserID:        s.userID(vars["user"]),
+			WorkflowState: "unsubmitted",
+		}
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	subType := r.FormValue("submission[submission_type]")
+	sub := s.submissions[submissionKey{a.ID, s.self}]
+	if sub == nil {
+		sub = &canvas.Submission{AssignmentID: a.ID, UserID: s.self}
+		s.submissions[submissionKey{a.ID, s.self}] = sub
+	}
+	switch subType {
+	case "online_text_entry":
+		sub.Body = r.FormValue("submission[body]")
+	case "online_url":
+		sub.URL = r.FormValue("submission[url]")
+	case "online_upload":
+		ids := formInts(r, "submission[file_ids][]")
+		for _, id := range ids {
+			if _, ok := s.files[id]; !ok {
+				badRequest(w, "invalid file id "+strconv.Itoa(id))
+				return
+			}
+		}
+		sub.FileIDs = ids
+	default:
+		badRequest(w, "invalid submission type")
+		return
+	}
+	sub.Type = subType
+	sub.Attempt++
+	sub.SubmittedAt = now()
+	sub.WorkflowState = "submitted"
+	sub.Late = !a.DueAt.IsZero() && sub.SubmittedAt.After(a.DueAt)
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (s *Server) gradeSubmission(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, a := s.assignment(w, vars)
+	if a == nil {
+		return
+	}
+	key := submissionKey{a.ID, s.userID(vars["user"])}
+	sub := s.submissions[key]
+	if sub == nil {
+		sub = &canvas.Submission{AssignmentID: a.ID, UserID: key.user, WorkflowState: "unsubmitted"}
+		s.submissions[key] = sub
+	}
+	if grade, ok := r.Form["submission[posted_grade]"]; ok {
+		sub.Grade = grade[0]
+		sub.Score, _ = strconv.ParseFloat(strings.TrimSuffix(grade[0], "%"), 64)
+		if strings.HasSuffix(grade[0], "%") {
+			sub.Score = sub.Score / 100 * a.PointsPossible
+		}
+		sub.GradedAt = now()
+		sub.GraderID = s.self
+		sub.WorkflowState = "graded"
+	}
+	if excused, ok := formBool(r, "submission[excuse]"); ok {
+		sub.Excused = excused
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
@@ -0,0 +1,357 @@
+package canvastest
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	canvas "github.com/ArchWizard56/go-canvas"
+)
+
+// Enrollment types used with Server.Enroll.
+const (
+	StudentEnrollment = "StudentEnrollment"
+	TeacherEnrollment = "TeacherEnrollment"
+	TaEnrollment      = "TaEnrollment"
+)
+
+type contextKey struct {
+	typ string // "Course" or "User"
+	id  int
+}
+
+func (k contextKey) rootName() string {
+	if k.typ == "Course" {
+		return "course files"
+	}
+	return "my files"
+}
+
+type course struct {
+	canvas.Course
+	// enrollments maps user ids to enrollment types
+	enrollments map[int]string
+}
+
+type file struct {
+	canvas.File
+	data []byte
+}
+
+type submissionKey struct {
+	assignment, user int
+}
+
+// AddUser adds a new user to the server.
+func (s *Server) AddUser(name string) canvas.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := &canvas.User{
+		ID:           s.newID(),
+		Name:         name,
+		ShortName:    name,
+		SortableName: name,
+		CreatedAt:    now(),
+	}
+	s.users[u.ID] = u
+	return *u
+}
+
+// CurrentUser returns the user that makes every request.
+func (s *Server) CurrentUser() canvas.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.users[s.self]
+}
+
+// AddCourse adds a new course with the current user enrolled as a teacher.
+func (s *Server) AddCourse(name string) canvas.Course {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &course{
+		Course: canvas.Course{
+			ID:            s.newID(),
+			Name:          name,
+			CourseCode:    name,
+			WorkflowState: "available",
+			CreatedAt:     now(),
+		},
+		enrollments: map[int]string{s.self: TeacherEnrollment},
+	}
+	s.courses[c.ID] = c
+	return c.Course
+}
+
+// Enroll adds a user to a course. The enrollment type is one of
+// StudentEnrollment, TeacherEnrollment or TaEnrollment.
+func (s *Server) Enroll(courseID, userID int, enrollmentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.courses[courseID]
+	if !ok {
+		return fmt.Errorf("no course with id %d", courseID)
+	}
+	if _, ok = s.users[userID]; !ok {
+		return fmt.Errorf("no user with id %d", userID)
+	}
+	c.enrollments[userID] = enrollmentType
+	return nil
+}
+
+// AddFile adds a file to a course. The folder path is relative to the
+// course's root folder and missing folders are created.
+func (s *Server) AddFile(courseID int, folderPath, name string, data []byte) (canvas.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.courses[courseID]; !ok {
+		return canvas.File{}, fmt.Errorf("no course with id %d", courseID)
+	}
+	folder := s.mkdirAll(contextKey{"Course", courseID}, folderPath)
+	f := s.createFile(folder, name, "", data, "overwrite")
+	return s.fileJSON(f), nil
+}
+
+// AddFolder adds a folder and any missing parents to a course. The path
+// is relative to the course's root folder.
+func (s *Server) AddFolder(courseID int, folderPath string) (canvas.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.courses[courseID]; !ok {
+		return canvas.Folder{}, fmt.Errorf("no course with id %d", courseID)
+	}
+	return s.folderJSON(s.mkdirAll(contextKey{"Course", courseID}, folderPath)), nil
+}
+
+// FileContents returns the contents of a file.
+func (s *Server) FileContents(id int) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), f.data...), true
+}
+
+// AddAssignment adds an assignment to a course.
+func (s *Server) AddAssignment(courseID int, a canvas.Assignment) (canvas.Assignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.courses[courseID]; !ok {
+		return canvas.Assignment{}, fmt.Errorf("no course with id %d", courseID)
+	}
+	return *s.createAssignment(courseID, a), nil
+}
+
+// Submissions returns the submissions for an assignment.
+func (s *Server) Submissions(assignmentID int) []canvas.Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var subs []canvas.Submission
+	for key, sub := range s.submissions {
+		if key.assignment == assignmentID {
+			subs = append(subs, *sub)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].UserID < subs[j].UserID })
+	return subs
+}
+
+func (s *Server) createAssignment(courseID int, a canvas.Assignment) *canvas.Assignment {
+	a.ID = s.newID()
+	a.CourseID = courseID
+	a.CreatedAt = now()
+	a.UpdatedAt = a.CreatedAt
+	a.HTMLURL = fmt.Sprintf("%s/courses/%d/assignments/%d", s.URL, courseID, a.ID)
+	s.assignments[a.ID] = &a
+	return &a
+}
+
+// root returns the root folder of a context, creating it if needed.
+func (s *Server) root(ctx contextKey) *canvas.Folder {
+	if id, ok := s.roots[ctx]; ok {
+		return s.folders[id]
+	}
+	f := &canvas.Folder{
+		ID:          s.newID(),
+		Foldername:  ctx.rootName(),
+		FullName:    ctx.rootName(),
+		ContextType: ctx.typ,
+		ContextID:   ctx.id,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	}
+	s.folders[f.ID] = f
+	s.roots[ctx] = f.ID
+	return f
+}
+
+func (s *Server) subfolders(parent int) []*canvas.Folder {
+	var list []*canvas.Folder
+	for _, f := range s.folders {
+		if f.ParentFolderID == parent && parent != 0 {
+			list = append(list, f)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Foldername < list[j].Foldername })
+	return list
+}
+
+func (s *Server) folderFiles(folder int) []*file {
+	var list []*file
+	for _, f := range s.files {
+		if f.FolderID == folder {
+			list = append(list, f)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].DisplayName < list[j].DisplayName })
+	return list
+}
+
+func (s *Server) subfolder(parent *canvas.Folder, name string) *canvas.Folder {
+	for _, f := range s.subfolders(parent.ID) {
+		if f.Foldername == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Server) mkdir(parent *canvas.Folder, name string) *canvas.Folder {
+	if f := s.subfolder(parent, name); f != nil {
+		return f
+	}
+	f := &canvas.Folder{
+		ID:             s.newID(),
+		ParentFolderID: parent.ID,
+		Foldername:     name,
+		FullName:       parent.FullName + "/" + name,
+		ContextType:    parent.ContextType,
+		ContextID:      parent.ContextID,
+		CreatedAt:      now(),
+		UpdatedAt:      now(),
+	}
+	s.folders[f.ID] = f
+	return f
+}
+
+func (s *Server) mkdirAll(ctx contextKey, p string) *canvas.Folder {
+	folder := s.root(ctx)
+	for _, name := range splitPath(p) {
+		folder = s.mkdir(folder, name)
+	}
+	return folder
+}
+
+// lookupPath finds every folder along a path starting with the root.
+func (s *Server) lookupPath(ctx contextKey, p string) []*canvas.Folder {
+	folder := s.root(ctx)
+	folders := []*canvas.Folder{folder}
+	for _, name := range splitPath(p) {
+		if folder = s.subfolder(folder, name); folder == nil {
+			return nil
+		}
+		folders = append(folders, folder)
+	}
+	return folders
+}
+
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(path.Clean("/"+p), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// createFile adds a file to a folder handling files with the same name
+// the way canvas does for the on_duplicate parameter.
+func (s *Server) createFile(folder *canvas.Folder, name, contentType string, data []byte, onDuplicate string) *file {
+	for _, existing := range s.folderFiles(folder.ID) {
+		if existing.DisplayName != name {
+			continue
+		}
+		if onDuplicate == "rename" {
+			name = s.uniqueName(folder, name)
+		} else {
+			delete(s.files, existing.ID)
+		}
+		break
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	f := &file{
+		File: canvas.File{
+			ID:          s.newID(),
+			FolderID:    folder.ID,
+			Filename:    name,
+			DisplayName: name,
+			ContentType: contentType,
+			Size:        len(data),
+			CreatedAt:   now(),
+			UpdatedAt:   now(),
+			ModifiedAt:  now(),
+		},
+		data: data,
+	}
+	f.URL = fmt.Sprintf("%s/files/%d/download", s.URL, f.ID)
+	if u, ok := s.users[s.self]; ok {
+		f.User = &canvas.UserDisplay{ID: u.ID, DisplayName: u.Name}
+	}
+	s.files[f.ID] = f
+	folder.UpdatedAt = now()
+	return f
+}
+
+func (s *Server) uniqueName(folder *canvas.Folder, name string) string {
+	taken := make(map[string]bool)
+	for _, f := range s.folderFiles(folder.ID) {
+		taken[f.DisplayName] = true
+	}
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+func (s *Server) fileJSON(f *file) canvas.File {
+	return f.File
+}
+
+func (s *Server) folderJSON(f *canvas.Folder) canvas.Folder {
+	folder := *f
+	folder.FilesCount = len(s.folderFiles(f.ID))
+	folder.FoldersCount = len(s.subfolders(f.ID))
+	folder.FilesURL = fmt.Sprintf("%s/api/v1/folders/%d/files", s.URL, f.ID)
+	folder.FoldersURL = fmt.Sprintf("%s/api/v1/folders/%d/folders", s.URL, f.ID)
+	return folder
+}
+
+// rename updates the full names of a folder and everything in it.
+func (s *Server) rename(f *canvas.Folder) {
+	if parent, ok := s.folders[f.ParentFolderID]; ok {
+		f.FullName = parent.FullName + "/" + f.Foldername
+	}
+	for _, sub := range s.subfolders(f.ID) {
+		s.rename(sub)
+	}
+}
+
+func (s *Server) removeFolder(f *canvas.Folder) {
+	for _, sub := range s.subfolders(f.ID) {
+		s.removeFolder(sub)
+	}
+	for _, file := range s.folderFiles(f.ID) {
+		delete(s.files, file.ID)
+	}
+	delete(s.folders, f.ID)
+}
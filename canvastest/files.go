@@ -0,0 +1,688 @@
+package canvastest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	canvas "github.com/ArchWizard56/go-canvas"
+)
+
+// maxUploadMemory is the most memory used to parse an upload, the
+// rest is stored in temporary files.
+const maxUploadMemory = 32 << 20
+
+// pendingUpload is a file upload that has been started with the api
+// but has not received the file contents yet.
+type pendingUpload struct {
+	folder      *canvas.Folder
+	name        string
+	contentType string
+	onDuplicate string
+}
+
+func (s *Server) fileRoutes() {
+	for _, ctx := range []string{"courses/:course", "users/:user"} {
+		s.handle("GET", ctx+"/files", s.listContextFiles)
+		s.handle("POST", ctx+"/files", s.startContextUpload)
+		s.handle("GET", ctx+"/files/quota", s.getQuota)
+		s.handle("GET", ctx+"/files/:file", s.getContextFile)
+		s.handle("GET", ctx+"/folders", s.listContextFolders)
+		s.handle("POST", ctx+"/folders", s.createContextFolder)
+		s.handle("GET", ctx+"/folders/root", s.getRootFolder)
+		s.handle("GET", ctx+"/folders/by_path/*", s.getFoldersByPath)
+		s.handle("GET", ctx+"/folders/:folder", s.getContextFolder)
+	}
+	s.handle("GET", "files/:file", s.getFile)
+	s.handle("PUT", "files/:file", s.updateFile)
+	s.handle("DELETE", "files/:file", s.deleteFile)
+	s.handle("GET", "files/:file/public_url", s.getPublicURL)
+	s.handle("GET", "folders/:folder", s.getFolder)
+	s.handle("PUT", "folders/:folder", s.updateFolder)
+	s.handle("DELETE", "folders/:folder", s.deleteFolder)
+	s.handle("GET", "folders/:folder/folders", s.listSubfolders)
+	s.handle("POST", "folders/:folder/folders", s.createSubfolder)
+	s.handle("GET", "folders/:folder/files", s.listFolderFiles)
+	s.handle("POST", "folders/:folder/files", s.startFolderUpload)
+	s.handle("POST", "folders/:folder/copy_file", s.copyFile)
+	s.handle("POST", "folders/:folder/copy_folder", s.copyFolder)
+	s.handle("POST", "courses/:course/assignments/:assignment/submissions/self/files", s.startSubmissionUpload)
+	s.handle("GET", "progress/:progress", s.getProgress)
+}
+
+// context finds the course or user that a request is for.
+func (s *Server) context(w http.ResponseWriter, vars map[string]string) (contextKey, bool) {
+	if _, ok := vars["course"]; ok {
+		c := s.course(w, vars)
+		if c == nil {
+			return contextKey{}, false
+		}
+		return contextKey{"Course", c.ID}, true
+	}
+	id := s.userID(vars["user"])
+	if _, ok := s.users[id]; !ok {
+		notFound(w)
+		return contextKey{}, false
+	}
+	return contextKey{"User", id}, true
+}
+
+func (s *Server) folder(w http.ResponseWriter, vars map[string]string) *canvas.Folder {
+	id, _ := strconv.Atoi(vars["folder"])
+	f, ok := s.folders[id]
+	if !ok {
+		notFound(w)
+		return nil
+	}
+	return f
+}
+
+func (s *Server) file(w http.ResponseWriter, vars map[string]string) *file {
+	id, _ := strconv.Atoi(vars["file"])
+	f, ok := s.files[id]
+	if !ok {
+		notFound(w)
+		return nil
+	}
+	return f
+}
+
+func (s *Server) inContext(ctx contextKey, folderID int) bool {
+	f, ok := s.folders[folderID]
+	return ok && f.ContextType == ctx.typ && f.ContextID == ctx.id
+}
+
+func (s *Server) listContextFiles(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	var files []*file
+	for _, f := range s.files {
+		if s.inContext(ctx, f.FolderID) {
+			files = append(files, f)
+		}
+	}
+	s.writeFiles(w, r, files)
+}
+
+func (s *Server) listFolderFiles(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if folder := s.folder(w, vars); folder != nil {
+		s.writeFiles(w, r, s.folderFiles(folder.ID))
+	}
+}
+
+// writeFiles filters and sorts files using the same parameters as canvas
+// and writes one page of them.
+func (s *Server) writeFiles(w http.ResponseWriter, r *http.Request, files []*file) {
+	var (
+		term    = strings.ToLower(r.FormValue("search_term"))
+		types   = r.Form["content_types[]"]
+		exclude = r.Form["exclude_content_types[]"]
+		list    = make([]*file, 0, len(files))
+	)
+	for _, f := range files {
+		if term != "" && !strings.Contains(strings.ToLower(f.DisplayName), term) {
+			continue
+		}
+		if len(types) > 0 && !matchContentType(types, f.ContentType) {
+			continue
+		}
+		if len(exclude) > 0 && matchContentType(exclude, f.ContentType) {
+			continue
+		}
+		list = append(list, f)
+	}
+	less := func(i, j int) bool { return list[i].DisplayName < list[j].DisplayName }
+	switch r.FormValue("sort") {
+	case "size":
+		less = func(i, j int) bool { return list[i].Size < list[j].Size }
+	case "created_at":
+		less = func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) }
+	case "updated_at":
+		less = func(i, j int) bool { return list[i].UpdatedAt.Before(list[j].UpdatedAt) }
+	case "content_type":
+		less = func(i, j int) bool { return list[i].ContentType < list[j].ContentType }
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].ID == list[j].ID {
+			return false
+		}
+		if r.FormValue("order") == "desc" {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+	items := make([]interface{}, len(list))
+	for i, f := range list {
+		items[i] = s.fileJSON(f)
+	}
+	s.paginate(w, r, items)
+}
+
+// matchContentType matches either full content types or
+// just the first part, ex. "image" matches "image/png".
+func matchContentType(types []string, contentType string) bool {
+	for _, t := range types {
+		for _, part := range strings.Split(t, ",") {
+			if part == contentType || strings.HasPrefix(contentType, part+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) getContextFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	f := s.file(w, vars)
+	if f == nil {
+		return
+	}
+	if !s.inContext(ctx, f.FolderID) {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.fileJSON(f))
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if f := s.file(w, vars); f != nil {
+		writeJSON(w, http.StatusOK, s.fileJSON(f))
+	}
+}
+
+func (s *Server) getPublicURL(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if f := s.file(w, vars); f != nil {
+		writeJSON(w, http.StatusOK, map[string]string{"public_url": f.URL})
+	}
+}
+
+func (s *Server) updateFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	f := s.file(w, vars)
+	if f == nil {
+		return
+	}
+	if parent, ok, err := s.parentFolder(r, s.folders[f.FolderID]); err != nil {
+		badRequest(w, err.Error())
+		return
+	} else if ok {
+		f.FolderID = parent.ID
+	}
+	if name := r.FormValue("name"); name != "" {
+		f.DisplayName = name
+	}
+	if hidden, ok := formBool(r, "hidden"); ok {
+		f.Hidden = hidden
+	}
+	if locked, ok := formBool(r, "locked"); ok {
+		f.Locked = locked
+	}
+	if err := setTime(r, "lock_at", &f.LockAt); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if err := setTime(r, "unlock_at", &f.UnlockAt); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	f.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, s.fileJSON(f))
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if f := s.file(w, vars); f != nil {
+		delete(s.files, f.ID)
+		writeJSON(w, http.StatusOK, s.fileJSON(f))
+	}
+}
+
+// parentFolder finds the folder given by the parent_folder_id or
+// parent_folder_path parameters. The path is relative to the root
+// folder of the context that the folder given is in.
+func (s *Server) parentFolder(r *http.Request, in *canvas.Folder) (*canvas.Folder, bool, error) {
+	if id := formInt(r, "parent_folder_id"); id != 0 {
+		parent, ok := s.folders[id]
+		if !ok {
+			return nil, false, fmt.Errorf("no folder with id %d", id)
+		}
+		return parent, true, nil
+	}
+	if p, ok := r.Form["parent_folder_path"]; ok && in != nil {
+		ctx := contextKey{in.ContextType, in.ContextID}
+		return s.mkdirAll(ctx, p[0]), true, nil
+	}
+	return nil, false, nil
+}
+
+func setTime(r *http.Request, key string, t *time.Time) error {
+	v, ok := r.Form[key]
+	if !ok {
+		return nil
+	}
+	if v[0] == "" {
+		*t = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, v[0])
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*t = parsed
+	return nil
+}
+
+func (s *Server) getQuota(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	var used int64
+	for _, f := range s.files {
+		if s.inContext(ctx, f.FolderID) {
+			used += int64(f.Size)
+		}
+	}
+	writeJSON(w, http.StatusOK, canvas.Quota{Quota: 500 << 20, QuotaUsed: used})
+}
+
+func (s *Server) listContextFolders(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	s.root(ctx)
+	var list []*canvas.Folder
+	for _, f := range s.folders {
+		if f.ContextType == ctx.typ && f.ContextID == ctx.id {
+			list = append(list, f)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].FullName < list[j].FullName })
+	s.writeFolders(w, r, list)
+}
+
+func (s *Server) listSubfolders(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if folder := s.folder(w, vars); folder != nil {
+		s.writeFolders(w, r, s.subfolders(folder.ID))
+	}
+}
+
+func (s *Server) writeFolders(w http.ResponseWriter, r *http.Request, folders []*canvas.Folder) {
+	items := make([]interface{}, len(folders))
+	for i, f := range folders {
+		items[i] = s.folderJSON(f)
+	}
+	s.paginate(w, r, items)
+}
+
+func (s *Server) getRootFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if ctx, ok := s.context(w, vars); ok {
+		writeJSON(w, http.StatusOK, s.folderJSON(s.root(ctx)))
+	}
+}
+
+// getFoldersByPath responds with every folder along the path
+// starting with the root folder.
+func (s *Server) getFoldersByPath(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	folders := s.lookupPath(ctx, vars["*"])
+	if folders == nil {
+		notFound(w)
+		return
+	}
+	res := make([]canvas.Folder, len(folders))
+	for i, f := range folders {
+		res[i] = s.folderJSON(f)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getContextFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	ctx, ok := s.context(w, vars)
+	if !ok {
+		return
+	}
+	folder := s.folder(w, vars)
+	if folder == nil {
+		return
+	}
+	if !s.inContext(ctx, folder.ID) {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.folderJSON(folder))
+}
+
+func (s *Server) getFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if folder := s.folder(w, vars); folder != nil {
+		writeJSON(w, http.StatusOK, s.folderJSON(folder))
+	}
+}
+
+func (s *Server) createContextFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if ctx, ok := s.context(w, vars); ok {
+		s.createFolder(w, r, s.root(ctx))
+	}
+}
+
+func (s *Server) createSubfolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if folder := s.folder(w, vars); folder != nil {
+		s.createFolder(w, r, folder)
+	}
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request, in *canvas.Folder) {
+	name := r.FormValue("name")
+	if name == "" || strings.Contains(name, "/") {
+		badRequest(w, "invalid folder name")
+		return
+	}
+	parent, ok, err := s.parentFolder(r, in)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if !ok {
+		parent = in
+	}
+	folder := s.mkdir(parent, name)
+	if hidden, ok := formBool(r, "hidden"); ok {
+		folder.Hidden = hidden
+	}
+	if locked, ok := formBool(r, "locked"); ok {
+		folder.Locked = locked
+	}
+	writeJSON(w, http.StatusOK, s.folderJSON(folder))
+}
+
+func (s *Server) updateFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	folder := s.folder(w, vars)
+	if folder == nil {
+		return
+	}
+	parent, ok, err := s.parentFolder(r, folder)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if ok {
+		for p := parent; p != nil; p = s.folders[p.ParentFolderID] {
+			if p.ID == folder.ID {
+				badRequest(w, "cannot move a folder into itself")
+				return
+			}
+		}
+		folder.ParentFolderID = parent.ID
+	}
+	if name := r.FormValue("name"); name != "" {
+		folder.Foldername = name
+	}
+	if hidden, ok := formBool(r, "hidden"); ok {
+		folder.Hidden = hidden
+	}
+	if locked, ok := formBool(r, "locked"); ok {
+		folder.Locked = locked
+	}
+	if position := formInt(r, "position"); position != 0 {
+		folder.Position = position
+	}
+	if err = setTime(r, "lock_at", &folder.LockAt); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if err = setTime(r, "unlock_at", &folder.UnlockAt); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	folder.UpdatedAt = now()
+	s.rename(folder)
+	writeJSON(w, http.StatusOK, s.folderJSON(folder))
+}
+
+func (s *Server) deleteFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	folder := s.folder(w, vars)
+	if folder == nil {
+		return
+	}
+	if folder.ParentFolderID == 0 {
+		badRequest(w, "cannot delete a root folder")
+		return
+	}
+	force, _ := formBool(r, "force")
+	if !force && (len(s.subfolders(folder.ID)) > 0 || len(s.folderFiles(folder.ID)) > 0) {
+		badRequest(w, "Cannot delete a folder with content")
+		return
+	}
+	res := s.folderJSON(folder)
+	s.removeFolder(folder)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) copyFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	dest := s.folder(w, vars)
+	if dest == nil {
+		return
+	}
+	src, ok := s.files[formInt(r, "source_file_id")]
+	if !ok {
+		notFound(w)
+		return
+	}
+	onDuplicate := r.FormValue("on_duplicate")
+	if onDuplicate == "" {
+		onDuplicate = "overwrite"
+	}
+	f := s.createFile(dest, src.DisplayName, src.ContentType, src.data, onDuplicate)
+	writeJSON(w, http.StatusOK, s.fileJSON(f))
+}
+
+func (s *Server) copyFolder(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	dest := s.folder(w, vars)
+	if dest == nil {
+		return
+	}
+	src, ok := s.folders[formInt(r, "source_folder_id")]
+	if !ok {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.folderJSON(s.copyTree(src, dest)))
+}
+
+func (s *Server) copyTree(src, dest *canvas.Folder) *canvas.Folder {
+	folder := s.mkdir(dest, src.Foldername)
+	for _, f := range s.folderFiles(src.ID) {
+		s.createFile(folder, f.DisplayName, f.ContentType, f.data, "rename")
+	}
+	for _, sub := range s.subfolders(src.ID) {
+		if sub.ID != folder.ID {
+			s.copyTree(sub, folder)
+		}
+	}
+	return folder
+}
+
+func (s *Server) startContextUpload(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if ctx, ok := s.context(w, vars); ok {
+		s.startUpload(w, r, s.mkdirAll(ctx, "unfiled"))
+	}
+}
+
+func (s *Server) startFolderUpload(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if folder := s.folder(w, vars); folder != nil {
+		s.startUpload(w, r, folder)
+	}
+}
+
+// startSubmissionUpload starts an upload into the current user's
+// submissions folder.
+func (s *Server) startSubmissionUpload(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	if _, a := s.assignment(w, vars); a == nil {
+		return
+	}
+	folder := s.mkdirAll(contextKey{"User", s.self}, "Submissions")
+	folder.ForSubmissions = true
+	s.startUpload(w, r, folder)
+}
+
+// startUpload is the first step of the canvas upload flow. The folder is
+// used when no parent folder parameters were given.
+func (s *Server) startUpload(w http.ResponseWriter, r *http.Request, folder *canvas.Folder) {
+	parent, ok, err := s.parentFolder(r, folder)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if ok {
+		folder = parent
+	}
+	up := &pendingUpload{
+		folder:      folder,
+		name:        r.FormValue("name"),
+		contentType: r.FormValue("content_type"),
+		onDuplicate: r.FormValue("on_duplicate"),
+	}
+	if up.onDuplicate == "" {
+		up.onDuplicate = "overwrite"
+	}
+	if fileURL := r.FormValue("url"); fileURL != "" {
+		s.uploadFromURL(w, up, fileURL)
+		return
+	}
+	if up.name == "" {
+		badRequest(w, "name is required")
+		return
+	}
+	token := newToken()
+	s.uploads[token] = up
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"upload_url":    s.URL + "/upload/" + token,
+		"upload_params": map[string]string{"token": token},
+		"file_param":    "file",
+	})
+}
+
+// uploadFromURL downloads a file and responds with a completed
+// progress object like canvas does once the download is done.
+func (s *Server) uploadFromURL(w http.ResponseWriter, up *pendingUpload, fileURL string) {
+	// the url may point back to this server so the lock cannot be held
+	s.mu.Unlock()
+	data, contentType, err := s.fetch(fileURL)
+	s.mu.Lock()
+
+	p := &canvas.Progress{
+		ID:            s.newID(),
+		ContextType:   "User",
+		ContextID:     s.self,
+		UserID:        s.self,
+		Tag:           "upload_via_url",
+		WorkflowState: "completed",
+		Completion:    100,
+		CreatedAt:     now(),
+		UpdatedAt:     now(),
+	}
+	p.URL = fmt.Sprintf("%s/api/v1/progress/%d", s.URL, p.ID)
+	if err != nil {
+		p.WorkflowState = "failed"
+		p.Message = err.Error()
+	} else {
+		if up.name == "" {
+			up.name = path.Base(fileURL)
+		}
+		if up.contentType == "" {
+			up.contentType = contentType
+		}
+		f := s.createFile(up.folder, up.name, up.contentType, data, up.onDuplicate)
+		p.Results, _ = json.Marshal(map[string]int{"id": f.ID})
+	}
+	s.progress[p.ID] = p
+	writeJSON(w, http.StatusOK, map[string]interface{}{"progress": p})
+}
+
+func (s *Server) fetch(fileURL string) ([]byte, string, error) {
+	resp, err := s.Client().Get(fileURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("could not download %s: %s", fileURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	return data, resp.Header.Get("Content-Type"), err
+}
+
+func (s *Server) getProgress(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	id, _ := strconv.Atoi(vars["progress"])
+	p, ok := s.progress[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// finishUpload receives the file contents, this is the second step
+// of the upload flow.
+func (s *Server) finishUpload(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	up, ok := s.uploads[vars["token"]]
+	if !ok {
+		notFound(w)
+		return
+	}
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	if r.FormValue("token") != vars["token"] {
+		badRequest(w, "missing upload params")
+		return
+	}
+	part, _, err := r.FormFile("file")
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	defer part.Close()
+	data, err := ioutil.ReadAll(part)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	delete(s.uploads, vars["token"])
+	f := s.createFile(up.folder, up.name, up.contentType, data, up.onDuplicate)
+	writeJSON(w, http.StatusCreated, s.fileJSON(f))
+}
+
+// downloadFile serves file contents. Range requests are supported.
+func (s *Server) downloadFile(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	f := s.file(w, vars)
+	if f == nil {
+		return
+	}
+	w.Header().Set("Content-Type", f.ContentType)
+	http.ServeContent(w, r, f.DisplayName, f.ModifiedAt, bytes.NewReader(f.data))
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,315 @@
+// Package canvastest provides an in-memory canvas server for tests.
+//
+// The server keeps courses, users, folders, files, assignments and
+// submissions in memory and serves the parts of the canvas api that
+// they use, including Link header pagination and file uploads.
+//
+//	srv := canvastest.NewServer()
+//	defer srv.Close()
+//	course := srv.AddCourse("Intro to Testing")
+//	srv.AddFile(course.ID, "notes", "week1.txt", []byte("..."))
+//
+//	c, err := srv.Canvas().GetCourse(course.ID)
+//	files, err := c.ListFiles()
+package canvastest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	canvas "github.com/ArchWizard56/go-canvas"
+)
+
+// DefaultToken is the api token accepted by a new Server.
+const DefaultToken = "canvastest-token"
+
+const defaultPerPage = 10
+
+// Server is an in-memory canvas server.
+type Server struct {
+	// URL is the base url of the server, ex. https://127.0.0.1:51234
+	URL string
+	// Host is the host and port of the server.
+	Host string
+	// Token is the only api token the server accepts.
+	Token string
+
+	srv    *httptest.Server
+	routes []route
+
+	mu          sync.Mutex
+	nextID      int
+	self        int
+	users       map[int]*canvas.User
+	courses     map[int]*course
+	folders     map[int]*canvas.Folder
+	files       map[int]*file
+	roots       map[contextKey]int
+	assignments map[int]*canvas.Assignment
+	submissions map[submissionKey]*canvas.Submission
+	uploads     map[string]*pendingUpload
+	progress    map[int]*canvas.Progress
+
+	rateLimit int // remaining requests, negative for no limit
+	requests  int
+}
+
+// NewServer starts a new server with a single user that is used as the
+// current user for every request.
+func NewServer() *Server {
+	s := &Server{
+		Token:       DefaultToken,
+		nextID:      1,
+		users:       make(map[int]*canvas.User),
+		courses:     make(map[int]*course),
+		folders:     make(map[int]*canvas.Folder),
+		files:       make(map[int]*file),
+		roots:       make(map[contextKey]int),
+		assignments: make(map[int]*canvas.Assignment),
+		submissions: make(map[submissionKey]*canvas.Submission),
+		uploads:     make(map[string]*pendingUpload),
+		progress:    make(map[int]*canvas.Progress),
+		rateLimit:   -1,
+	}
+	s.routes = s.makeRoutes()
+	s.srv = httptest.NewTLSServer(s)
+	s.URL = s.srv.URL
+	s.Host = strings.TrimPrefix(s.URL, "https://")
+	s.self = s.AddUser("Test User").ID
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns an http client that trusts the server's certificate.
+func (s *Server) Client() *http.Client {
+	return s.srv.Client()
+}
+
+// Canvas returns a canvas client that talks to the server.
+func (s *Server) Canvas() *canvas.Canvas {
+	return canvas.WithClient(s.Client(), s.Token, s.Host)
+}
+
+// SetRateLimit will make the server reject every request after the next
+// n requests as if the api rate limit was exceeded. A negative n removes
+// the limit.
+func (s *Server) SetRateLimit(n int) {
+	s.mu.Lock()
+	s.rateLimit = n
+	s.mu.Unlock()
+}
+
+// Requests returns the number of api requests the server has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	api := strings.HasPrefix(r.URL.Path, "/api/v1/")
+	if api {
+		s.requests++
+		if r.Header.Get("Authorization") != "Bearer "+s.Token {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="canvas-lms"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"status": "unauthenticated",
+				"errors": []map[string]string{{"message": "Invalid access token."}},
+			})
+			return
+		}
+		if s.rateLimit == 0 {
+			w.Header().Set("X-Rate-Limit-Remaining", "0")
+			http.Error(w, "403 Forbidden (Rate Limit Exceeded)", http.StatusForbidden)
+			return
+		}
+		if s.rateLimit > 0 {
+			s.rateLimit--
+			w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(s.rateLimit))
+		}
+	}
+	if err := r.ParseForm(); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/")
+	for _, rt := range s.routes {
+		if rt.api != api || rt.method != r.Method {
+			continue
+		}
+		if vars, ok := rt.match(p); ok {
+			rt.handler(w, r, vars)
+			return
+		}
+	}
+	notFound(w)
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, vars map[string]string)
+
+type route struct {
+	method  string
+	api     bool
+	parts   []string
+	handler handlerFunc
+}
+
+// match will match a path to the route's pattern. Parts that start with
+// ':' match any single path element and a trailing '*' matches the rest
+// of the path.
+func (rt *route) match(p string) (map[string]string, bool) {
+	elems := strings.Split(p, "/")
+	vars := make(map[string]string)
+	for i, part := range rt.parts {
+		if part == "*" {
+			vars["*"] = strings.Join(elems[i:], "/")
+			return vars, true
+		}
+		if i >= len(elems) {
+			return nil, false
+		}
+		if strings.HasPrefix(part, ":") {
+			vars[part[1:]] = elems[i]
+		} else if part != elems[i] {
+			return nil, false
+		}
+	}
+	return vars, len(elems) == len(rt.parts)
+}
+
+func (s *Server) handle(method, pattern string, h handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		api:     true,
+		parts:   strings.Split(pattern, "/"),
+		handler: h,
+	})
+}
+
+func (s *Server) makeRoutes() []route {
+	s.routes = nil
+	s.userRoutes()
+	s.courseRoutes()
+	s.fileRoutes()
+	// routes that are not part of the api
+	s.routes = append(s.routes,
+		route{method: "GET", parts: []string{"files", ":file", "download"}, handler: s.downloadFile},
+		route{method: "POST", parts: []string{"upload", ":token"}, handler: s.finishUpload},
+	)
+	return s.routes
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func notFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"errors": []map[string]string{{"message": "The specified resource does not exist."}},
+	})
+}
+
+func badRequest(w http.ResponseWriter, msg string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"message": msg})
+}
+
+// paginate writes one page of items along with the Link header.
+func (s *Server) paginate(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	perPage, err := strconv.Atoi(r.FormValue("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	page, err := strconv.Atoi(r.FormValue("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	last := (len(items) + perPage - 1) / perPage
+	if last == 0 {
+		last = 1
+	}
+
+	link := func(p int, rel string) string {
+		q := url.Values{}
+		for k, v := range r.URL.Query() {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return fmt.Sprintf(`<%s/api/v1%s?%s>; rel="%s"`,
+			s.URL, strings.TrimPrefix(r.URL.Path, "/api/v1"), q.Encode(), rel)
+	}
+	links := []string{link(page, "current")}
+	if page < last {
+		links = append(links, link(page+1, "next"))
+	}
+	if page > 1 {
+		links = append(links, link(page-1, "prev"))
+	}
+	links = append(links, link(1, "first"), link(last, "last"))
+	w.Header().Set("Link", strings.Join(links, ","))
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+// formInt gets an integer parameter, zero if it is missing or invalid.
+func formInt(r *http.Request, key string) int {
+	n, _ := strconv.Atoi(r.FormValue(key))
+	return n
+}
+
+func formBool(r *http.Request, key string) (value, ok bool) {
+	v, ok := r.Form[key]
+	if !ok || len(v) == 0 {
+		return false, false
+	}
+	b, err := strconv.ParseBool(v[0])
+	return err == nil && b, true
+}
+
+func formInts(r *http.Request, key string) []int {
+	var ids []int
+	for _, v := range r.Form[key] {
+		for _, part := range strings.Split(v, ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,206 @@
+package canvastest
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+
+	canvas "github.com/ArchWizard56/go-canvas"
+	"github.com/matryer/is"
+)
+
+func TestServer_Courses(t *testing.T) {
+	is := is.New(t)
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Canvas()
+
+	course := srv.AddCourse("Testing 101")
+	student := srv.AddUser("Student")
+	is.NoErr(srv.Enroll(course.ID, student.ID, StudentEnrollment))
+
+	u, err := c.CurrentUser()
+	is.NoErr(err)
+	is.Equal(u.ID, srv.CurrentUser().ID)
+	is.Equal(u.Name, "Test User")
+
+	courses, err := c.Courses()
+	is.NoErr(err)
+	is.Equal(len(courses), 1)
+	is.Equal(courses[0].Name, "Testing 101")
+
+	crs, err := c.GetCourse(course.ID)
+	is.NoErr(err)
+	is.Equal(crs.ID, course.ID)
+	users, err := crs.Users(canvas.OptStudent)
+	is.NoErr(err)
+	is.Equal(len(users), 1)
+	is.Equal(users[0].ID, student.ID)
+
+	_, err = c.GetCourse(course.ID + 1000)
+	is.True(err != nil)
+	_, ok := err.(*canvas.AuthError)
+	is.True(ok) // not found is an *AuthError
+}
+
+func TestServer_Files(t *testing.T) {
+	is := is.New(t)
+	srv := NewServer()
+	defer srv.Close()
+	course := srv.AddCourse("Files")
+	for i := 0; i < 25; i++ {
+		_, err := srv.AddFile(course.ID, "notes", fmt.Sprintf("%02d.txt", i), []byte("note"))
+		is.NoErr(err)
+	}
+	crs, err := srv.Canvas().GetCourse(course.ID)
+	is.NoErr(err)
+
+	files, err := crs.ListFiles()
+	is.NoErr(err)
+	is.Equal(len(files), 25) // should get every page
+	seen := make(map[int]bool)
+	for _, f := range files {
+		is.True(!seen[f.ID]) // each file should only be listed once
+		seen[f.ID] = true
+	}
+
+	folders, err := crs.FolderPath("notes")
+	is.NoErr(err)
+	is.Equal(len(folders), 2)
+	is.Equal(folders[0].FullName, "course files")
+	is.Equal(folders[1].FullName, "course files/notes")
+	is.Equal(folders[1].FilesCount, 25)
+
+	dir, err := crs.CreateFolder("/notes/week1")
+	is.NoErr(err)
+	is.Equal(dir.FullName, "course files/notes/week1")
+	is.Equal(dir.ParentFolderID, folders[1].ID)
+
+	f, err := dir.UploadFile("hello.txt", strings.NewReader("hello world"))
+	is.NoErr(err)
+	is.Equal(f.FolderID, dir.ID)
+	is.Equal(f.Size, 11)
+	is.Equal(f.ContentType, "text/plain")
+	data, ok := srv.FileContents(f.ID)
+	is.True(ok)
+	is.Equal(string(data), "hello world")
+
+	var buf bytes.Buffer
+	_, err = f.WriteTo(&buf)
+	is.NoErr(err)
+	is.Equal(buf.String(), "hello world")
+
+	dup, err := dir.UploadFile("hello.txt", strings.NewReader("again"), canvas.Opt("on_duplicate", "rename"))
+	is.NoErr(err)
+	is.Equal(dup.DisplayName, "hello-1.txt")
+	is.NoErr(f.Replace(strings.NewReader("replaced")))
+	data, _ = srv.FileContents(f.ID)
+	is.Equal(string(data), "replaced")
+
+	is.NoErr(dup.Rename("renamed.txt"))
+	is.Equal(dup.DisplayName, "renamed.txt")
+	is.True(dir.Delete() != nil) // folder is not empty
+	is.NoErr(dir.Delete(canvas.Opt("force", true)))
+	_, ok = srv.FileContents(dup.ID)
+	is.True(!ok)
+}
+
+func TestServer_Submissions(t *testing.T) {
+	is := is.New(t)
+	srv := NewServer()
+	defer srv.Close()
+	course := srv.AddCourse("Assignments")
+	crs, err := srv.Canvas().GetCourse(course.ID)
+	is.NoErr(err)
+
+	a, err := crs.CreateAssignment(canvas.Assignment{
+		Name:            "Homework",
+		PointsPossible:  10,
+		SubmissionTypes: []string{"online_upload"},
+	})
+	is.NoErr(err)
+	is.Equal(a.Name, "Homework")
+	is.Equal(a.CourseID, course.ID)
+	is.Equal(a.SubmissionTypes, []string{"online_upload"})
+	list, err := crs.ListAssignments()
+	is.NoErr(err)
+	is.Equal(len(list), 1)
+
+	f, err := list[0].SubmitFile("answers.txt", strings.NewReader("42"))
+	is.NoErr(err)
+	is.Equal(f.DisplayName, "answers.txt")
+	subs := srv.Submissions(a.ID)
+	is.Equal(len(subs), 0) // uploading does not submit
+
+	req, err := http.NewRequest("POST", fmt.Sprintf(
+		"%s/api/v1/courses/%d/assignments/%d/submissions?submission[submission_type]=online_upload&submission[file_ids][]=%d",
+		srv.URL, course.ID, a.ID, f.ID), nil)
+	is.NoErr(err)
+	req.Header.Set("Authorization", "Bearer "+srv.Token)
+	resp, err := srv.Client().Do(req)
+	is.NoErr(err)
+	resp.Body.Close()
+	is.Equal(resp.StatusCode, http.StatusCreated)
+
+	subs = srv.Submissions(a.ID)
+	is.Equal(len(subs), 1)
+	is.Equal(subs[0].UserID, srv.CurrentUser().ID)
+	is.Equal(subs[0].WorkflowState, "submitted")
+	is.Equal(subs[0].Attempt, 1)
+}
+
+func TestServer_Errors(t *testing.T) {
+	is := is.New(t)
+	srv := NewServer()
+	defer srv.Close()
+
+	_, err := canvas.WithClient(srv.Client(), "wrong", srv.Host).CurrentUser()
+	is.True(err != nil)
+	_, ok := err.(*canvas.AuthError)
+	is.True(ok)
+
+	c := srv.Canvas()
+	srv.SetRateLimit(1)
+	_, err = c.CurrentUser()
+	is.NoErr(err)
+	_, err = c.CurrentUser()
+	is.True(canvas.IsRateLimit(err))
+	srv.SetRateLimit(-1)
+	_, err = c.CurrentUser()
+	is.NoErr(err)
+	is.Equal(srv.Requests(), 4)
+}
+
+func TestServer_UploadFromURL(t *testing.T) {
+	is := is.New(t)
+	srv := NewServer()
+	defer srv.Close()
+	course := srv.AddCourse("Uploads")
+	src, err := srv.AddFile(course.ID, "", "source.txt", []byte("from a url"))
+	is.NoErr(err)
+	crs, err := srv.Canvas().GetCourse(course.ID)
+	is.NoErr(err)
+
+	root, err := crs.Root()
+	is.NoErr(err)
+	f, err := root.UploadFromURL(src.URL, canvas.Opt("name", "copy.txt"))
+	is.NoErr(err)
+	is.Equal(f.DisplayName, "copy.txt")
+	is.Equal(f.FolderID, root.ID)
+	data, _ := srv.FileContents(f.ID)
+	is.Equal(string(data), "from a url")
+
+	b, err := fs.ReadFile(crs.FS(), "copy.txt")
+	is.NoErr(err)
+	is.Equal(string(b), "from a url")
+	var names []string
+	err = fs.WalkDir(crs.FS(), ".", func(p string, d fs.DirEntry, err error) error {
+		names = append(names, p)
+		return err
+	})
+	is.NoErr(err)
+	is.Equal(names, []string{".", "copy.txt", "source.txt"})
+}
@@ -0,0 +1,311 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Conversation events are used to update many conversations at once.
+const (
+	MarkConversationRead   = "mark_as_read"
+	MarkConversationUnread = "mark_as_unread"
+	StarConversation       = "star"
+	UnstarConversation     = "unstar"
+	ArchiveConversation    = "archive"
+	DestroyConversation    = "destroy"
+)
+
+// conversationAttachmentsFolder is the folder that canvas
+// expects conversation attachments to be uploaded to.
+const conversationAttachmentsFolder = "conversation attachments"
+
+// Conversation is a conversation.
+//
+// https://canvas.instructure.com/doc/api/conversations.html
+type Conversation struct {
+	ID            int       `json:"id"`
+	Subject       string    `json:"subject"`
+	WorkflowState string    `json:"workflow_state"`
+	LastMessage   string    `json:"last_message"`
+	StartAt       time.Time `json:"start_at"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	MessageCount  int       `json:"message_count"`
+	Subscribed    bool      `json:"subscribed"`
+	Private       bool      `json:"private"`
+	Starred       bool      `json:"starred"`
+	// Properties is a list of extra properties for the conversation.
+	// Can include "last_author", "has_attachments", and "has_media_objects".
+	Properties []string `json:"properties"`
+	// Audience is a list of the ids of the other participants.
+	Audience []int `json:"audience"`
+	// AudienceContexts maps each course or group id shared with the
+	// audience to the enrollment types held in that context.
+	AudienceContexts struct {
+		Courses map[string][]string `json:"courses"`
+		Groups  map[string][]string `json:"groups"`
+	} `json:"audience_contexts"`
+	AvatarURL    string                     `json:"avatar_url"`
+	Participants []*ConversationParticipant `json:"participants"`
+	Visible      bool                       `json:"visible"`
+	ContextName  string                     `json:"context_name"`
+	ContextCode  string                     `json:"context_code"`
+	// Messages is only populated when getting a single conversation.
+	Messages []*ConversationMessage `json:"messages"`
+
+	client doer
+}
+
+// ConversationParticipant is a user taking part in a conversation.
+type ConversationParticipant struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	FullName  string `json:"full_name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// ConversationMessage is a single message in a conversation.
+type ConversationMessage struct {
+	ID                   int                    `json:"id"`
+	CreatedAt            time.Time              `json:"created_at"`
+	Body                 string                 `json:"body"`
+	AuthorID             int                    `json:"author_id"`
+	Generated            bool                   `json:"generated"`
+	ParticipatingUserIDs []int                  `json:"participating_user_ids"`
+	ForwardedMessages    []*ConversationMessage `json:"forwarded_messages"`
+	Attachments          []*File                `json:"attachments"`
+}
+
+// Conversations returns a list of the current user's conversations. Use
+// UnreadConversations, StarredConversations, ArchivedConversations, or
+// SentConversations to filter the list.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.index
+func (c *Canvas) Conversations(opts ...Option) ([]*Conversation, error) {
+	return listConversations(c.client, opts)
+}
+
+// Conversations returns a list of the current user's conversations.
+func Conversations(opts ...Option) ([]*Conversation, error) {
+	return ca.Conversations(opts...)
+}
+
+// GetConversation will get a conversation along with its messages.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.show
+func (c *Canvas) GetConversation(id int, opts ...Option) (*Conversation, error) {
+	conv := &Conversation{client: c.client}
+	return conv, getjson(c.client, conv, optEnc(opts), "/conversations/%d", id)
+}
+
+// GetConversation will get a conversation along with its messages.
+func GetConversation(id int, opts ...Option) (*Conversation, error) {
+	return ca.GetConversation(id, opts...)
+}
+
+// CreateConversation will start a new conversation. The recipients can be
+// user ids or context codes such as "course_123" or "group_456".
+// Canvas may create more than one conversation when sending to many
+// recipients.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.create
+func (c *Canvas) CreateConversation(
+	recipients []string,
+	subject, body string,
+	opts ...Option,
+) ([]*Conversation, error) {
+	q := params{
+		"recipients[]": recipients,
+		"subject":      {subject},
+		"body":         {body},
+	}
+	q.Add(opts)
+	resp, err := post(c.client, "/conversations", q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	convs := make([]*Conversation, 0, 1)
+	if err = json.NewDecoder(resp.Body).Decode(&convs); err != nil {
+		return nil, err
+	}
+	for _, conv := range convs {
+		conv.client = c.client
+	}
+	return convs, nil
+}
+
+// CreateConversation will start a new conversation.
+func CreateConversation(recipients []string, subject, body string, opts ...Option) ([]*Conversation, error) {
+	return ca.CreateConversation(recipients, subject, body, opts...)
+}
+
+// UpdateConversations will apply an event to many conversations at once. The
+// event should be one of MarkConversationRead, MarkConversationUnread,
+// StarConversation, UnstarConversation, ArchiveConversation, or
+// DestroyConversation. Canvas will update the conversations in the
+// background and returns the job's progress.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.batch_update
+func (c *Canvas) UpdateConversations(ids []int, event string) (*Progress, error) {
+	q := params{"event": {event}}
+	for _, id := range ids {
+		q["conversation_ids[]"] = append(q["conversation_ids[]"], strconv.Itoa(id))
+	}
+	resp, err := put(c.client, "/conversations", q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	p := &Progress{client: c.client}
+	return p, json.NewDecoder(resp.Body).Decode(p)
+}
+
+// UpdateConversations will apply an event to many conversations at once.
+func UpdateConversations(ids []int, event string) (*Progress, error) {
+	return ca.UpdateConversations(ids, event)
+}
+
+// UnreadCount will get the number of unread conversations
+// for the current user.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.unread_count
+func (c *Canvas) UnreadCount() (int, error) {
+	res := struct {
+		Count json.Number `json:"unread_count"`
+	}{}
+	if err := getjson(c.client, &res, nil, "/conversations/unread_count"); err != nil {
+		return 0, err
+	}
+	n, err := res.Count.Int64()
+	return int(n), err
+}
+
+// UnreadCount will get the number of unread conversations
+// for the current user.
+func UnreadCount() (int, error) { return ca.UnreadCount() }
+
+// UploadConversationAttachment will upload a file to the current user's
+// conversation attachments folder so that it can be sent in a message.
+func (c *Canvas) UploadConversationAttachment(filename string, r io.Reader, opts ...Option) (*File, error) {
+	params := newFileUploadParams(filename, opts)
+	params.ParentFolderPath = conversationAttachmentsFolder
+	return uploadFile(c.client, r, "/users/self/files", params)
+}
+
+// UploadConversationAttachment will upload a file to the current user's
+// conversation attachments folder so that it can be sent in a message.
+func UploadConversationAttachment(filename string, r io.Reader, opts ...Option) (*File, error) {
+	return ca.UploadConversationAttachment(filename, r, opts...)
+}
+
+// ListMessages will get the full message history of the conversation.
+func (c *Conversation) ListMessages() ([]*ConversationMessage, error) {
+	if err := getjson(c.client, c, nil, "/conversations/%d", c.ID); err != nil {
+		return nil, err
+	}
+	return c.Messages, nil
+}
+
+// Reply will add a message to the conversation. Attachments should
+// be uploaded with UploadConversationAttachment first.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.add_message
+func (c *Conversation) Reply(body string, attachments []*File, opts ...Option) (*ConversationMessage, error) {
+	q := params{"body": {body}}
+	for _, f := range attachments {
+		q["attachment_ids[]"] = append(q["attachment_ids[]"], strconv.Itoa(f.ID))
+	}
+	q.Add(opts)
+	resp, err := post(c.client, fmt.Sprintf("/conversations/%d/add_message", c.ID), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// canvas only sends back the newly added message
+	res := Conversation{}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Messages) == 0 {
+		return nil, errors.New("no message returned")
+	}
+	msg := res.Messages[0]
+	c.Messages = append([]*ConversationMessage{msg}, c.Messages...)
+	c.MessageCount++
+	c.LastMessage = msg.Body
+	return msg, nil
+}
+
+// MarkRead will mark the conversation as read.
+func (c *Conversation) MarkRead() error {
+	return c.update(params{"conversation[workflow_state]": {"read"}})
+}
+
+// MarkUnread will mark the conversation as unread.
+func (c *Conversation) MarkUnread() error {
+	return c.update(params{"conversation[workflow_state]": {"unread"}})
+}
+
+// Archive will archive the conversation.
+func (c *Conversation) Archive() error {
+	return c.update(params{"conversation[workflow_state]": {"archived"}})
+}
+
+// Star will star the conversation.
+func (c *Conversation) Star() error {
+	return c.update(params{"conversation[starred]": {"true"}})
+}
+
+// Unstar will unstar the conversation.
+func (c *Conversation) Unstar() error {
+	return c.update(params{"conversation[starred]": {"false"}})
+}
+
+// Delete will delete the conversation.
+//
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.destroy
+func (c *Conversation) Delete() error {
+	resp, err := delete(c.client, fmt.Sprintf("/conversations/%d", c.ID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// https://canvas.instructure.com/doc/api/conversations.html#method.conversations.update
+func (c *Conversation) update(q params) error {
+	resp, err := put(c.client, fmt.Sprintf("/conversations/%d", c.ID), q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(c)
+}
+
+func listConversations(d doer, opts []Option) (convs []*Conversation, err error) {
+	ch := make(chan *Conversation)
+	pager := newPaginatedList(d, "/conversations", func(r io.Reader) error {
+		list := make([]*Conversation, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, c := range list {
+			c.client = d
+			ch <- c
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case c := <-ch:
+			convs = append(convs, c)
+		case err := <-errs:
+			return convs, err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestConversations(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	mux.HandleFunc("/api/v1/conversations", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.Method {
+		case "GET":
+			is.Equal(q.Get("scope"), "starred")
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(`[{"id":1,"subject":"hello","starred":true,
+				"properties":["last_author","has_attachments"],
+				"audience":[2],
+				"audience_contexts":{"courses":{"5":["StudentEnrollment"]},"groups":{}},
+				"participants":[{"id":2,"name":"someone"}]}]`))
+		case "POST":
+			is.Equal(q["recipients[]"], []string{"2", "course_5"})
+			is.Equal(q.Get("subject"), "hi")
+			w.Write([]byte(`[{"id":3,"subject":"hi"}]`))
+		case "PUT":
+			is.Equal(q["conversation_ids[]"], []string{"1", "3"})
+			is.Equal(q.Get("event"), "mark_as_read")
+			w.Write([]byte(`{"id":9,"workflow_state":"queued"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/conversations/unread_count", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"unread_count":"4"}`))
+	})
+	mux.HandleFunc("/api/v1/conversations/1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"id":1,"messages":[{"id":11,"body":"second"},{"id":10,"body":"first"}]}`))
+		case "PUT":
+			is.Equal(r.URL.Query().Get("conversation[workflow_state]"), "archived")
+			w.Write([]byte(`{"id":1,"workflow_state":"archived"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/conversations/1/add_message", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query()["attachment_ids[]"], []string{"20"})
+		w.Write([]byte(`{"id":1,"messages":[{"id":12,"body":"reply","attachments":[{"id":20}]}]}`))
+	})
+
+	convs, err := Conversations(StarredConversations)
+	is.NoErr(err)
+	is.Equal(len(convs), 1)
+	conv := convs[0]
+	is.Equal(conv.Properties, []string{"last_author", "has_attachments"})
+	is.Equal(conv.AudienceContexts.Courses["5"], []string{"StudentEnrollment"})
+	is.Equal(conv.Participants[0].Name, "someone")
+
+	msgs, err := conv.ListMessages()
+	is.NoErr(err)
+	is.Equal(len(msgs), 2)
+	msg, err := conv.Reply("reply", []*File{{ID: 20}})
+	is.NoErr(err)
+	is.Equal(msg.ID, 12)
+	is.Equal(msg.Attachments[0].ID, 20)
+	is.NoErr(conv.Archive())
+	is.Equal(conv.WorkflowState, "archived")
+
+	created, err := CreateConversation([]string{"2", "course_5"}, "hi", "body")
+	is.NoErr(err)
+	is.Equal(created[0].ID, 3)
+
+	p, err := UpdateConversations([]int{1, 3}, MarkConversationRead)
+	is.NoErr(err)
+	is.Equal(p.ID, 9)
+	is.True(!p.Done())
+
+	n, err := UnreadCount()
+	is.NoErr(err)
+	is.Equal(n, 4)
+}
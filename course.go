@@ -36,6 +36,7 @@ type Course struct {
 	Locale               string        `json:"locale"`
 	Enrollments          []*Enrollment `json:"enrollments"`
 	TotalStudents        int           `json:"total_students"`
+	IsFavorite           bool          `json:"is_favorite"` // set by GetCourse, Courses, CoursesChan, User.Courses, and FavoriteCourses
 	Calendar             struct {
 		// ICS Download is the download link for the calendar
 		ICSDownload string `json:"ics"`
@@ -262,8 +263,10 @@ func (c *Course) SearchUsers(term string, opts ...Option) (users []*User, err er
 
 // User gets a specific user.
 func (c *Course) User(id int, opts ...Option) (*User, error) {
-	u := &User{client: c.client}
-	return u, getjson(c.client, u, optEnc(opts), "/courses/%d/users/%d", c.ID, id)
+	u := &User{}
+	err := getjson(c.client, u, optEnc(opts), "/courses/%d/users/%d", c.ID, id)
+	u.setclient(c.client)
+	return u, err
 }
 
 // Assignment will get an assignment from the course given an id.
@@ -466,15 +469,8 @@ func (a *Assignment) SubmitFile(filename string, r io.Reader, opts ...Option) (*
 	params := fileUploadParams{
 		Name:        filename,
 		OnDuplicate: "rename",
-		ContentType: filenameContentType(filename),
 	}
 	params.setOptions(opts)
-	if hasstat, ok := r.(interface{ Stat() (os.FileInfo, error) }); ok {
-		stat, err := hasstat.Stat()
-		if err == nil {
-			params.Size = int(stat.Size())
-		}
-	}
 	endpoint := fmt.Sprintf("/courses/%d/assignments/%d/submissions/self/files", a.CourseID, a.ID)
 	return uploadFile(a.client, r, endpoint, &params)
 }
@@ -542,10 +538,14 @@ type AssignmentOverride struct {
 
 // DiscussionTopics return a list of the course discussion topics.
 func (c *Course) DiscussionTopics(opts ...Option) ([]*DiscussionTopic, error) {
+	return listDiscussionTopics(c.client, c.id("courses/%d"), opts)
+}
+
+func listDiscussionTopics(d doer, ctxpath string, opts []Option) ([]*DiscussionTopic, error) {
 	ch := make(chan *DiscussionTopic)
 	pager := newPaginatedList(
-		c.client, fmt.Sprintf("/courses/%d/discussion_topics", c.ID),
-		sendDiscussionTopicFunc(ch), opts,
+		d, fmt.Sprintf("/%s/discussion_topics", ctxpath),
+		sendDiscussionTopicFunc(d, ctxpath, ch), opts,
 	)
 	topics := make([]*DiscussionTopic, 0)
 	errs := pager.start()
@@ -645,6 +645,9 @@ func (c *Course) SetErrorHandler(f errorHandlerFunc) {
 
 func (c *Course) setclient(d doer) {
 	c.client = d
+	for _, e := range c.Enrollments {
+		e.client = d
+	}
 }
 
 // Term is a school term. One school year.
@@ -724,6 +727,8 @@ type Enrollment struct {
 	CurrentPeriodUnpostedFinalScore   float64 `json:"current_period_unposted_final_score"`
 	CurrentPeriodUnpostedCurrentGrade string  `json:"current_period_unposted_current_grade"`
 	CurrentPeriodUnpostedFinalGrade   string  `json:"current_period_unposted_final_grade"`
+
+	client doer
 }
 
 // Quizzes will get all the course quizzes
@@ -741,6 +746,7 @@ func getQuizzes(client doer, courseID int, opts []Option) (qs []*Quiz, err error
 }
 
 func getQuiz(client doer, course, quiz int, opts []Option) (q *Quiz, err error) {
+	q = &Quiz{}
 	return q, getjson(client, q, optEnc(opts), "courses/%d/quizzes/%d", course, quiz)
 }
 
@@ -835,11 +841,12 @@ func (c *Course) assignmentspager(ch chan *Assignment, params []Option) *paginat
 }
 
 func (c *Course) collectUsers(path string, opts []Option) (users []*User, err error) {
+	return listUsers(c.client, fmt.Sprintf(path, c.ID), opts)
+}
+
+func listUsers(d doer, path string, opts []Option) (users []*User, err error) {
 	ch := make(chan *User)
-	errs := newPaginatedList(
-		c.client, fmt.Sprintf(path, c.ID),
-		sendUserFunc(c.client, ch), opts,
-	).start()
+	errs := newPaginatedList(d, path, sendUserFunc(d, ch), opts).start()
 	for {
 		select {
 		case u := <-ch:
@@ -890,7 +897,7 @@ func sendUserFunc(d doer, ch chan *User) sendFunc {
 			return err
 		}
 		for _, u := range list {
-			u.client = d
+			u.setclient(d)
 			ch <- u
 		}
 		return nil
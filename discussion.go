@@ -0,0 +1,382 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// ErrRatingNotAllowed is returned when trying to rate an entry of a
+// discussion topic that does not allow rating.
+var ErrRatingNotAllowed = errors.New("discussion topic does not allow rating")
+
+// DiscussionTopic will get a course's discussion topic by id.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.show
+func (c *Course) DiscussionTopic(id int, opts ...Option) (*DiscussionTopic, error) {
+	return getDiscussionTopic(c.client, c.id("courses/%d"), id, opts)
+}
+
+// CreateDiscussionTopic will create a new discussion topic in the course.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics.create
+func (c *Course) CreateDiscussionTopic(t DiscussionTopic) (*DiscussionTopic, error) {
+	return createDiscussionTopic(c.client, c.id("courses/%d"), &t)
+}
+
+// DiscussionTopic will get a group's discussion topic by id.
+func (g *Group) DiscussionTopic(id int, opts ...Option) (*DiscussionTopic, error) {
+	return getDiscussionTopic(g.client, g.id("groups/%d"), id, opts)
+}
+
+// CreateDiscussionTopic will create a new discussion topic in the group.
+func (g *Group) CreateDiscussionTopic(t DiscussionTopic) (*DiscussionTopic, error) {
+	return createDiscussionTopic(g.client, g.id("groups/%d"), &t)
+}
+
+// Update will send the topic's editable fields to canvas and update
+// the topic with the response.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics.update
+func (dt *DiscussionTopic) Update() error {
+	q, err := query.Values(dt)
+	if err != nil {
+		return err
+	}
+	resp, err := put(dt.client, dt.endpoint(""), q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(dt); err != nil {
+		return err
+	}
+	dt.setclient(dt.client)
+	return nil
+}
+
+// Delete will delete the discussion topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics.destroy
+func (dt *DiscussionTopic) Delete() error {
+	return dt.send("DELETE", "")
+}
+
+// Entries will list the top level entries of the discussion topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.entries
+func (dt *DiscussionTopic) Entries(opts ...Option) ([]*DiscussionEntry, error) {
+	return dt.listEntries(dt.endpoint("/entries"), opts)
+}
+
+// Post will create a new top level entry in the discussion topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.add_entry
+func (dt *DiscussionTopic) Post(message string, opts ...Option) (*DiscussionEntry, error) {
+	return dt.postEntry(dt.endpoint("/entries"), message, opts)
+}
+
+// View will get the full threaded view of the discussion topic. The
+// entries are returned as a tree where each entry holds its replies.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.view
+func (dt *DiscussionTopic) View(opts ...Option) (*DiscussionView, error) {
+	view := &DiscussionView{}
+	err := getjson(dt.client, view, optEnc(opts), dt.endpoint("/view"))
+	if err != nil {
+		return nil, err
+	}
+	unread := make(map[int]bool, len(view.UnreadEntries))
+	for _, id := range view.UnreadEntries {
+		unread[id] = true
+	}
+	var walk func([]*DiscussionEntry)
+	walk = func(entries []*DiscussionEntry) {
+		for _, e := range entries {
+			e.settopic(dt)
+			if unread[e.ID] {
+				e.ReadState = "unread"
+			} else {
+				e.ReadState = "read"
+			}
+			e.Rating = view.EntryRatings[e.ID]
+			walk(e.Replies)
+		}
+	}
+	walk(view.View)
+	walk(view.NewEntries)
+	return view, nil
+}
+
+// MarkRead will mark the topic as read.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_topic_read
+func (dt *DiscussionTopic) MarkRead() error {
+	if err := dt.send("PUT", "/read"); err != nil {
+		return err
+	}
+	dt.ReadState = "read"
+	return nil
+}
+
+// MarkUnread will mark the topic as unread.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_topic_unread
+func (dt *DiscussionTopic) MarkUnread() error {
+	if err := dt.send("DELETE", "/read"); err != nil {
+		return err
+	}
+	dt.ReadState = "unread"
+	return nil
+}
+
+// MarkAllRead will mark the topic and all of its entries as read.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_all_read
+func (dt *DiscussionTopic) MarkAllRead() error {
+	if err := dt.send("PUT", "/read_all"); err != nil {
+		return err
+	}
+	dt.ReadState = "read"
+	dt.UnreadCount = 0
+	return nil
+}
+
+// MarkAllUnread will mark the topic and all of its entries as unread.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_all_unread
+func (dt *DiscussionTopic) MarkAllUnread() error {
+	if err := dt.send("DELETE", "/read_all"); err != nil {
+		return err
+	}
+	dt.ReadState = "unread"
+	return nil
+}
+
+// Subscribe will subscribe the current user to the topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.subscribe_topic
+func (dt *DiscussionTopic) Subscribe() error {
+	if err := dt.send("PUT", "/subscribed"); err != nil {
+		return err
+	}
+	dt.Subscribed = true
+	return nil
+}
+
+// Unsubscribe will unsubscribe the current user from the topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.unsubscribe_topic
+func (dt *DiscussionTopic) Unsubscribe() error {
+	if err := dt.send("DELETE", "/subscribed"); err != nil {
+		return err
+	}
+	dt.Subscribed = false
+	return nil
+}
+
+func (dt *DiscussionTopic) send(method, suffix string) error {
+	resp, err := do(dt.client, newreq(method, dt.endpoint(suffix), nil))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (dt *DiscussionTopic) endpoint(suffix string) string {
+	return fmt.Sprintf("/%s/discussion_topics/%d", dt.path, dt.ID) + suffix
+}
+
+func (dt *DiscussionTopic) postEntry(path, message string, opts []Option) (*DiscussionEntry, error) {
+	q := params{"message": {message}}
+	q.Add(opts)
+	resp, err := post(dt.client, path, q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	e := &DiscussionEntry{}
+	err = json.NewDecoder(resp.Body).Decode(e)
+	e.settopic(dt)
+	return e, err
+}
+
+func (dt *DiscussionTopic) listEntries(path string, opts []Option) (entries []*DiscussionEntry, err error) {
+	ch := make(chan *DiscussionEntry)
+	pager := newPaginatedList(dt.client, path, func(r io.Reader) error {
+		list := make([]*DiscussionEntry, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, e := range list {
+			e.settopic(dt)
+			ch <- e
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case e := <-ch:
+			entries = append(entries, e)
+		case err := <-errs:
+			return entries, err
+		}
+	}
+}
+
+// DiscussionView is the full threaded view of a discussion topic.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.view
+type DiscussionView struct {
+	UnreadEntries []int              `json:"unread_entries"`
+	ForcedEntries []int              `json:"forced_entries"`
+	EntryRatings  map[int]int        `json:"entry_ratings"`
+	Participants  []UserDisplay      `json:"participants"`
+	View          []*DiscussionEntry `json:"view"`
+	NewEntries    []*DiscussionEntry `json:"new_entries"`
+}
+
+// UserDisplay is an abbreviated user used when displaying a list
+// of users.
+type UserDisplay struct {
+	ID             int    `json:"id"`
+	DisplayName    string `json:"display_name"`
+	AvatarImageURL string `json:"avatar_image_url"`
+	HTMLURL        string `json:"html_url"`
+}
+
+// DiscussionEntry is an entry or reply in a discussion topic.
+type DiscussionEntry struct {
+	ID              int                `json:"id"`
+	UserID          int                `json:"user_id"`
+	ParentID        int                `json:"parent_id"`
+	EditorID        int                `json:"editor_id"`
+	UserName        string             `json:"user_name"`
+	Message         string             `json:"message"`
+	ReadState       string             `json:"read_state"`
+	ForcedReadState bool               `json:"forced_read_state"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	Deleted         bool               `json:"deleted"`
+	RatingCount     int                `json:"rating_count"`
+	RatingSum       int                `json:"rating_sum"`
+	Attachment      *File              `json:"attachment"`
+	Attachments     []*File            `json:"attachments"`
+	Replies         []*DiscussionEntry `json:"replies"`
+	RecentReplies   []*DiscussionEntry `json:"recent_replies"`
+	HasMoreReplies  bool               `json:"has_more_replies"`
+
+	// Rating is the current user's rating of the entry. It is
+	// only set for entries returned by DiscussionTopic.View.
+	Rating int `json:"-"`
+
+	topic *DiscussionTopic
+}
+
+// Reply will post a reply to the entry.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.add_reply
+func (e *DiscussionEntry) Reply(message string, opts ...Option) (*DiscussionEntry, error) {
+	return e.topic.postEntry(e.endpoint("/replies"), message, opts)
+}
+
+// ListReplies will list the replies to the entry.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.replies
+func (e *DiscussionEntry) ListReplies(opts ...Option) ([]*DiscussionEntry, error) {
+	return e.topic.listEntries(e.endpoint("/replies"), opts)
+}
+
+// MarkRead will mark the entry as read.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_entry_read
+func (e *DiscussionEntry) MarkRead() error {
+	if err := e.topic.send("PUT", e.entryPath("/read")); err != nil {
+		return err
+	}
+	e.ReadState = "read"
+	return nil
+}
+
+// MarkUnread will mark the entry as unread.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.mark_entry_unread
+func (e *DiscussionEntry) MarkUnread() error {
+	if err := e.topic.send("DELETE", e.entryPath("/read")); err != nil {
+		return err
+	}
+	e.ReadState = "unread"
+	return nil
+}
+
+// Rate will rate the entry. The rating must be 0 or 1 and the
+// topic must allow rating.
+//
+// https://canvas.instructure.com/doc/api/discussion_topics.html#method.discussion_topics_api.rate_entry
+func (e *DiscussionEntry) Rate(rating int) error {
+	if !e.topic.AllowRating {
+		return ErrRatingNotAllowed
+	}
+	if rating != 0 && rating != 1 {
+		return fmt.Errorf("rating must be 0 or 1, got %d", rating)
+	}
+	resp, err := post(
+		e.topic.client, e.endpoint("/rating"),
+		params{"rating": {strconv.Itoa(rating)}},
+	)
+	if err != nil {
+		return err
+	}
+	if err = resp.Body.Close(); err != nil {
+		return err
+	}
+	e.Rating = rating
+	return nil
+}
+
+func (e *DiscussionEntry) settopic(dt *DiscussionTopic) {
+	e.topic = dt
+	if e.Attachment != nil {
+		e.Attachment.setclient(dt.client)
+	}
+	for _, f := range e.Attachments {
+		f.setclient(dt.client)
+	}
+}
+
+func (e *DiscussionEntry) entryPath(suffix string) string {
+	return fmt.Sprintf("/entries/%d", e.ID) + suffix
+}
+
+func (e *DiscussionEntry) endpoint(suffix string) string {
+	return e.topic.endpoint(e.entryPath(suffix))
+}
+
+func getDiscussionTopic(d doer, ctxpath string, id int, opts []Option) (*DiscussionTopic, error) {
+	dt := &DiscussionTopic{path: ctxpath}
+	err := getjson(d, dt, optEnc(opts), "/%s/discussion_topics/%d", ctxpath, id)
+	dt.setclient(d)
+	return dt, err
+}
+
+func createDiscussionTopic(d doer, ctxpath string, t *DiscussionTopic) (*DiscussionTopic, error) {
+	q, err := query.Values(t)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(d, fmt.Sprintf("/%s/discussion_topics", ctxpath), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	dt := &DiscussionTopic{path: ctxpath}
+	err = json.NewDecoder(resp.Body).Decode(dt)
+	dt.setclient(d)
+	return dt, err
+}
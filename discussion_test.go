@@ -0,0 +1,152 @@
+package canvas
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestDiscussionTopics(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q.Get("title"), "Week 1")
+		is.Equal(q.Get("discussion_type"), "threaded")
+		is.Equal(q.Get("allow_rating"), "true")
+		is.Equal(q.Get("pinned"), "")
+		w.Write([]byte(`{"id":2,"title":"Week 1","allow_rating":true,"attachments":[{"id":3,"display_name":"notes.pdf"}]}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2/view", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"unread_entries":[5],
+			"entry_ratings":{"4":1},
+			"participants":[{"id":7,"display_name":"someone"}],
+			"view":[{"id":4,"message":"first","replies":[{"id":5,"parent_id":4,"message":"reply"}]}]}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2/entries/4/replies", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("message"), "another reply")
+		w.Write([]byte(`{"id":6,"parent_id":4,"message":"another reply"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2/entries/5/read", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	var ratings int
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2/entries/4/rating", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		ratings++
+		if r.URL.Query().Get("rating") != "0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":[{"message":"rating failed"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/2/subscribed", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	course := &Course{ID: 1, client: client}
+	topic, err := course.CreateDiscussionTopic(DiscussionTopic{
+		Title:          "Week 1",
+		DiscussionType: "threaded",
+		AllowRating:    true,
+	})
+	is.NoErr(err)
+	is.Equal(topic.ID, 2)
+	is.Equal(len(topic.Attachments), 1)
+	is.Equal(topic.Attachments[0].DisplayName, "notes.pdf")
+	is.True(topic.Attachments[0].client != nil)
+
+	view, err := topic.View()
+	is.NoErr(err)
+	is.Equal(len(view.Participants), 1)
+	is.Equal(len(view.View), 1)
+	entry := view.View[0]
+	is.Equal(entry.ReadState, "read")
+	is.Equal(entry.Rating, 1)
+	is.Equal(len(entry.Replies), 1)
+	reply := entry.Replies[0]
+	is.Equal(reply.ReadState, "unread")
+	is.NoErr(reply.MarkRead())
+	is.Equal(reply.ReadState, "read")
+
+	e, err := entry.Reply("another reply")
+	is.NoErr(err)
+	is.Equal(e.ParentID, 4)
+	is.NoErr(entry.Rate(0))
+	is.Equal(entry.Rating, 0)
+	is.True(entry.Rate(1) != nil)
+	is.Equal(entry.Rating, 0) // not changed when canvas fails
+	is.True(entry.Rate(5) != nil)
+	is.Equal(ratings, 2) // invalid ratings are not sent
+
+	is.NoErr(topic.Subscribe())
+	is.True(topic.Subscribed)
+	is.NoErr(topic.Unsubscribe())
+	is.True(!topic.Subscribed)
+
+	topic.AllowRating = false
+	is.Equal(entry.Rate(1), ErrRatingNotAllowed)
+}
+
+func TestPostAnnouncement(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("name"), "syllabus.txt")
+		is.Equal(r.URL.Query().Get("parent_folder_path"), "announcements")
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{"key":"value"},"file_param":"file"}`))
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		is.NoErr(r.ParseMultipartForm(1 << 20))
+		is.Equal(r.FormValue("key"), "value")
+		f, _, err := r.FormFile("file")
+		is.NoErr(err)
+		f.Close()
+		w.Write([]byte(`{"id":10,"display_name":"syllabus.txt"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		is.Equal(q.Get("is_announcement"), "true")
+		is.Equal(q.Get("delayed_post_at"), "2020-09-01T08:00:00Z")
+		is.True(strings.Contains(q.Get("message"), `href="/courses/1/files/10"`))
+		w.Write([]byte(`{"id":3,"title":"Welcome","is_announcement":true}`))
+	})
+	mux.HandleFunc("/api/v1/courses/2/files", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"errors":[{"message":"quota exceeded"}]}`))
+	})
+
+	results := PostAnnouncement(
+		[]string{"course_1", "course_2", "bad"},
+		&Announcement{
+			Title:            "Welcome",
+			Message:          "<p>hello</p>",
+			DelayedPostAt:    time.Date(2020, 9, 1, 8, 0, 0, 0, time.UTC),
+			AttachmentFolder: "announcements",
+			Attachments: []AnnouncementAttachment{
+				{Filename: "syllabus.txt", Data: []byte("read me")},
+			},
+		},
+	)
+	is.Equal(len(results), 3)
+	is.NoErr(results[0].Err)
+	is.Equal(results[0].Topic.ID, 3)
+	is.Equal(results[0].Files[0].ID, 10)
+	is.True(results[1].Err != nil)
+	is.True(results[1].Topic == nil)
+	is.True(results[2].Err != nil)
+}
@@ -0,0 +1,228 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrIncompleteDownload is returned when the amount of data downloaded
+// does not match the size of the file.
+var ErrIncompleteDownload = errors.New("incomplete download")
+
+// DownloadProgressFunc is called as a file is downloaded. Written includes
+// any data that was already on disk when a download is resumed.
+type DownloadProgressFunc func(f *File, written, total int64)
+
+// Download will save the file to the local filename. If the local file
+// already holds the start of the file, only the rest of it is requested
+// so that interrupted downloads can be resumed. Files that are already
+// the full size are not downloaded again. A local file that was last
+// modified before the file was updated in canvas is an old version and
+// is downloaded again from the start.
+func (f *File) Download(filename string) error {
+	return f.download(filename, nil)
+}
+
+// Downloader downloads many files at the same time.
+type Downloader struct {
+	// Concurrency is the max number of files that are
+	// downloaded at the same time. Defaults to 4.
+	Concurrency int
+	// Progress is called as each file is downloaded,
+	// it may be called from multiple goroutines.
+	Progress DownloadProgressFunc
+}
+
+// DownloadResult is the outcome of downloading one file.
+type DownloadResult struct {
+	File *File
+	// Path is the local path of the downloaded file.
+	Path string
+	Err  error
+}
+
+// Download will download the files into a local directory using their
+// names as the local file names. The directory is created if it does not
+// exist. Partial files left by an earlier run are resumed. The results are
+// in the same order as the files.
+func (dl *Downloader) Download(dir string, files []*File) []DownloadResult {
+	concurrency := dl.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		results = make([]DownloadResult, len(files))
+		seen    = make(map[string]bool, len(files))
+	)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		for i, f := range files {
+			results[i] = DownloadResult{File: f, Err: err}
+		}
+		return results
+	}
+	for i, f := range files {
+		res := &results[i]
+		res.File = f
+		res.Path, res.Err = localPath(dir, f.Name())
+		if res.Err != nil {
+			continue
+		}
+		if seen[res.Path] {
+			res.Err = fmt.Errorf("more than one file named %q", f.Name())
+			continue
+		}
+		seen[res.Path] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(f *File, res *DownloadResult) {
+			defer func() { <-sem; wg.Done() }()
+			var progress func(int64)
+			if dl.Progress != nil {
+				progress = func(n int64) { dl.Progress(f, n, int64(f.Size)) }
+			}
+			res.Err = f.download(res.Path, progress)
+		}(f, res)
+	}
+	wg.Wait()
+	return results
+}
+
+func (f *File) download(filename string, progress func(int64)) error {
+	fh, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := fh.Stat()
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	offset, size := info.Size(), int64(f.Size)
+	if info.ModTime().Before(f.UpdatedAt) {
+		// the local data is from an older version of the file
+		offset = 0
+	}
+	if size > 0 && offset == size {
+		if progress != nil {
+			progress(offset)
+		}
+		return fh.Close()
+	}
+	if offset > size {
+		offset = 0
+	}
+
+	body, err := f.open(offset)
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	defer body.Close()
+	offset = body.n // the server may not support ranges
+	if err = fh.Truncate(offset); err != nil {
+		fh.Close()
+		return err
+	}
+	if _, err = fh.Seek(offset, io.SeekStart); err != nil {
+		fh.Close()
+		return err
+	}
+	var w io.Writer = fh
+	if progress != nil {
+		progress(offset)
+		w = &progressWriter{w: fh, n: offset, fn: progress}
+	}
+	if _, err = io.Copy(w, body); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// open will request the file's contents starting at offset. The
+// returned body will fail with ErrIncompleteDownload if it ends
+// before the size of the file is reached.
+func (f *File) open(offset int64) (*verifyReader, error) {
+	if f.URL == "" {
+		return nil, errors.New("file has no download url")
+	}
+	req, err := http.NewRequest("GET", f.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	var d doer = http.DefaultClient
+	if f.client != nil {
+		d = f.client
+	}
+	resp, err := d.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	start := int64(0)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		start, err = contentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not download %q: %s", f.Name(), resp.Status)
+	}
+	return &verifyReader{body: resp.Body, n: start, size: int64(f.Size)}, nil
+}
+
+func contentRangeStart(header string) (int64, error) {
+	// ex. "bytes 100-199/200"
+	rng := strings.TrimPrefix(header, "bytes ")
+	i := strings.IndexByte(rng, '-')
+	if i < 0 {
+		return 0, fmt.Errorf("bad Content-Range %q", header)
+	}
+	return strconv.ParseInt(rng[:i], 10, 64)
+}
+
+// verifyReader checks that the whole file was read.
+type verifyReader struct {
+	body io.ReadCloser
+	n    int64
+	size int64
+}
+
+func (vr *verifyReader) Read(b []byte) (int, error) {
+	n, err := vr.body.Read(b)
+	vr.n += int64(n)
+	if err == io.EOF && vr.size > 0 && vr.n != vr.size {
+		return n, fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteDownload, vr.n, vr.size)
+	}
+	return n, err
+}
+
+func (vr *verifyReader) Close() error { return vr.body.Close() }
+
+type progressWriter struct {
+	w  io.Writer
+	n  int64
+	fn func(int64)
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.n += int64(n)
+	pw.fn(pw.n)
+	return n, err
+}
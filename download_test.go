@@ -0,0 +1,113 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestFile_Download(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "canvas-download")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	content := strings.Repeat("0123456789", 100)
+	var ranges []string
+	mux.HandleFunc("/files/1/download", func(w http.ResponseWriter, r *http.Request) {
+		// canvas redirects to a storage server that must not get the token
+		is.True(r.Header.Get("Authorization") != "")
+		http.Redirect(w, r, "https://storage.example.com/1", http.StatusFound)
+	})
+	mux.HandleFunc("/1", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.Header.Get("Authorization"), "")
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "file.txt", time.Time{}, strings.NewReader(content))
+	})
+	mux.HandleFunc("/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content[:10])) // cut off
+	})
+	mux.HandleFunc("/3", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "access denied", http.StatusForbidden)
+	})
+
+	file := &File{
+		ID: 1, DisplayName: "file.txt", Size: len(content),
+		URL: "https://" + DefaultHost + "/files/1/download", client: client,
+	}
+	var buf bytes.Buffer
+	_, err = file.WriteTo(&buf)
+	is.NoErr(err)
+	is.Equal(buf.String(), content)
+
+	// resume a partial download
+	local := filepath.Join(dir, "file.txt")
+	is.NoErr(ioutil.WriteFile(local, []byte(content[:400]), 0644))
+	is.NoErr(file.Download(local))
+	is.Equal(ranges[len(ranges)-1], "bytes=400-")
+	b, err := ioutil.ReadFile(local)
+	is.NoErr(err)
+	is.Equal(string(b), content)
+
+	// already complete
+	n := len(ranges)
+	is.NoErr(file.Download(local))
+	is.Equal(len(ranges), n)
+
+	// the file changed in canvas after the partial download
+	// so the old data cannot be resumed
+	is.NoErr(ioutil.WriteFile(local, []byte(strings.Repeat("x", 400)), 0644))
+	old := time.Now().Add(-time.Hour)
+	is.NoErr(os.Chtimes(local, old, old))
+	changed := *file
+	changed.UpdatedAt = time.Now()
+	is.NoErr(changed.Download(local))
+	is.Equal(ranges[len(ranges)-1], "")
+	b, err = ioutil.ReadFile(local)
+	is.NoErr(err)
+	is.Equal(string(b), content)
+
+	short := &File{DisplayName: "short.txt", Size: len(content), URL: server.URL + "/2", client: client}
+	_, err = short.WriteTo(ioutil.Discard)
+	is.True(errors.Is(err, ErrIncompleteDownload))
+	denied := &File{DisplayName: "denied.txt", Size: 5, URL: server.URL + "/3", client: client}
+	_, err = denied.AsReadCloser()
+	is.True(err != nil)
+
+	var (
+		mu       sync.Mutex
+		progress = map[string]int64{}
+	)
+	dl := &Downloader{
+		Concurrency: 2,
+		Progress: func(f *File, written, total int64) {
+			mu.Lock()
+			progress[f.Name()] = written
+			mu.Unlock()
+		},
+	}
+	other := *file
+	other.DisplayName = "other.txt"
+	results := dl.Download(filepath.Join(dir, "new"), []*File{file, &other, short})
+	is.Equal(len(results), 3)
+	is.NoErr(results[0].Err)
+	is.NoErr(results[1].Err)
+	is.True(errors.Is(results[2].Err, ErrIncompleteDownload))
+	is.Equal(results[1].Path, filepath.Join(dir, "new", "other.txt"))
+	is.Equal(progress["file.txt"], int64(len(content)))
+	is.Equal(progress["other.txt"], int64(len(content)))
+	b, err = ioutil.ReadFile(filepath.Join(dir, "new", "other.txt"))
+	is.NoErr(err)
+	is.Equal(string(b), content)
+}
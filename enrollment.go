@@ -0,0 +1,218 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// EnrollmentType is the type of an enrollment.
+type EnrollmentType string
+
+const (
+	// StudentEnrollment is the enrollment type for students
+	StudentEnrollment EnrollmentType = "StudentEnrollment"
+	// TeacherEnrollment is the enrollment type for teachers
+	TeacherEnrollment EnrollmentType = "TeacherEnrollment"
+	// TAEnrollment is the enrollment type for teaching assistants
+	TAEnrollment EnrollmentType = "TaEnrollment"
+	// ObserverEnrollment is the enrollment type for observers
+	ObserverEnrollment EnrollmentType = "ObserverEnrollment"
+	// DesignerEnrollment is the enrollment type for designers
+	DesignerEnrollment EnrollmentType = "DesignerEnrollment"
+)
+
+// ListEnrollments will get the course's enrollments. The enrollment type
+// options (OptTeacher, OptStudent, etc.) can be used as filters.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.index
+func (c *Course) ListEnrollments(opts ...Option) ([]*Enrollment, error) {
+	return listEnrollments(c.client, c.id("/courses/%d/enrollments"), opts)
+}
+
+// Enroll will enroll a user in the course. The section and state are
+// optional and will be ignored if they are zero valued, extra options will
+// be sent as "enrollment[<option>]" parameters.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.create
+func (c *Course) Enroll(
+	userID int,
+	typ EnrollmentType,
+	section int,
+	state string,
+	opts ...Option,
+) (*Enrollment, error) {
+	p := params{
+		"enrollment[user_id]": {strconv.Itoa(userID)},
+		"enrollment[type]":    {string(typ)},
+	}
+	if section > 0 {
+		p.Set("enrollment[course_section_id]", strconv.Itoa(section))
+	}
+	if state != "" {
+		p.Set("enrollment[enrollment_state]", state)
+	}
+	p.Add(toPrefixedOpts("enrollment", opts))
+	resp, err := post(c.client, c.id("/courses/%d/enrollments"), p)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	e := &Enrollment{client: c.client}
+	return e, json.NewDecoder(resp.Body).Decode(e)
+}
+
+// ListEnrollments will get the user's enrollments. The enrollment type
+// options (OptTeacher, OptStudent, etc.) can be used as filters.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.index
+func (u *User) ListEnrollments(opts ...Option) ([]*Enrollment, error) {
+	return listEnrollments(u.client, u.id("/users/%d/enrollments"), opts)
+}
+
+// Conclude will conclude the enrollment.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.destroy
+func (e *Enrollment) Conclude() error {
+	return e.remove("conclude")
+}
+
+// Delete will delete the enrollment.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.destroy
+func (e *Enrollment) Delete() error {
+	return e.remove("delete")
+}
+
+// Deactivate will set the enrollment as inactive.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.destroy
+func (e *Enrollment) Deactivate() error {
+	return e.remove("deactivate")
+}
+
+// Reactivate will reactivate an inactive enrollment.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.reactivate
+func (e *Enrollment) Reactivate() error {
+	resp, err := put(e.client, e.path("/reactivate"), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(e)
+}
+
+// Accept will accept a pending course invitation.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.accept
+func (e *Enrollment) Accept() error {
+	return e.respond("accept")
+}
+
+// Reject will reject a pending course invitation.
+//
+// https://canvas.instructure.com/doc/api/enrollments.html#method.enrollments_api.reject
+func (e *Enrollment) Reject() error {
+	return e.respond("reject")
+}
+
+func (e *Enrollment) remove(task string) error {
+	resp, err := delete(e.client, e.path(""), params{"task": {task}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(e)
+}
+
+func (e *Enrollment) respond(action string) error {
+	resp, err := post(e.client, e.path("/"+action), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	res := struct {
+		Success bool `json:"success"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return err
+	}
+	if !res.Success {
+		return errors.New("could not " + action + " enrollment")
+	}
+	return nil
+}
+
+func (e *Enrollment) path(suffix string) string {
+	return fmt.Sprintf("/courses/%d/enrollments/%d%s", e.CourseID, e.ID, suffix)
+}
+
+func listEnrollments(d doer, path string, opts []Option) (enrollments []*Enrollment, err error) {
+	ch := make(chan *Enrollment)
+	pager := newPaginatedList(
+		d, path, sendEnrollmentsFunc(d, ch),
+		enrollmentFilters(opts),
+	)
+	errs := pager.start()
+	for {
+		select {
+		case e := <-ch:
+			enrollments = append(enrollments, e)
+		case err := <-errs:
+			return enrollments, err
+		}
+	}
+}
+
+func sendEnrollmentsFunc(d doer, ch chan *Enrollment) sendFunc {
+	return func(r io.Reader) error {
+		list := make([]*Enrollment, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, e := range list {
+			e.client = d
+			ch <- e
+		}
+		return nil
+	}
+}
+
+// the enrollment type options use the values for other endpoints so
+// they need to be translated to the enrollments api types
+var enrollmentTypes = map[string]EnrollmentType{
+	"student":  StudentEnrollment,
+	"teacher":  TeacherEnrollment,
+	"ta":       TAEnrollment,
+	"observer": ObserverEnrollment,
+	"designer": DesignerEnrollment,
+}
+
+// enrollmentFilters will convert any "enrollment_type" options into one
+// "type[]" option so that multiple filters are not overwritten.
+func enrollmentFilters(opts []Option) []Option {
+	var (
+		filters = make([]Option, 0, len(opts))
+		types   []string
+	)
+	for _, o := range opts {
+		switch o.Name() {
+		case "enrollment_type", "type", "type[]":
+			for _, v := range o.Value() {
+				if t, ok := enrollmentTypes[v]; ok {
+					v = string(t)
+				}
+				types = append(types, v)
+			}
+		default:
+			filters = append(filters, o)
+		}
+	}
+	if len(types) > 0 {
+		filters = append(filters, ArrayOpt("type", types...))
+	}
+	return filters
+}
@@ -0,0 +1,160 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Favorite is a course or group that has been added to the current user's
+// favorites.
+//
+// https://canvas.instructure.com/doc/api/favorites.html
+type Favorite struct {
+	ContextID   int    `json:"context_id"`
+	ContextType string `json:"context_type"`
+}
+
+// FavoriteCourses returns the current user's list of favorite courses.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.list_favorite_courses
+func (c *Canvas) FavoriteCourses(opts ...Option) ([]*Course, error) {
+	return favoriteCourses(c.client, opts)
+}
+
+// FavoriteCourses returns the current user's list of favorite courses.
+func FavoriteCourses(opts ...Option) ([]*Course, error) {
+	return ca.FavoriteCourses(opts...)
+}
+
+// FavoriteGroups returns the current user's list of favorite groups.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.list_favorite_groups
+func (c *Canvas) FavoriteGroups(opts ...Option) ([]*Group, error) {
+	return listGroups(c.client, "/users/self/favorites/groups", opts)
+}
+
+// FavoriteGroups returns the current user's list of favorite groups.
+func FavoriteGroups(opts ...Option) ([]*Group, error) {
+	return ca.FavoriteGroups(opts...)
+}
+
+// AddFavoriteCourse will add a course to the current user's favorites.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.add_favorite_course
+func (c *Canvas) AddFavoriteCourse(id int) (*Favorite, error) {
+	return favorite(c.client, "POST", "courses", id)
+}
+
+// AddFavoriteCourse will add a course to the current user's favorites.
+func AddFavoriteCourse(id int) (*Favorite, error) { return ca.AddFavoriteCourse(id) }
+
+// RemoveFavoriteCourse will remove a course from the current user's favorites.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.remove_favorite_course
+func (c *Canvas) RemoveFavoriteCourse(id int) (*Favorite, error) {
+	return favorite(c.client, "DELETE", "courses", id)
+}
+
+// RemoveFavoriteCourse will remove a course from the current user's favorites.
+func RemoveFavoriteCourse(id int) (*Favorite, error) { return ca.RemoveFavoriteCourse(id) }
+
+// AddFavoriteGroup will add a group to the current user's favorites.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.add_favorite_groups
+func (c *Canvas) AddFavoriteGroup(id int) (*Favorite, error) {
+	return favorite(c.client, "POST", "groups", id)
+}
+
+// AddFavoriteGroup will add a group to the current user's favorites.
+func AddFavoriteGroup(id int) (*Favorite, error) { return ca.AddFavoriteGroup(id) }
+
+// RemoveFavoriteGroup will remove a group from the current user's favorites.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.remove_favorite_groups
+func (c *Canvas) RemoveFavoriteGroup(id int) (*Favorite, error) {
+	return favorite(c.client, "DELETE", "groups", id)
+}
+
+// RemoveFavoriteGroup will remove a group from the current user's favorites.
+func RemoveFavoriteGroup(id int) (*Favorite, error) { return ca.RemoveFavoriteGroup(id) }
+
+// ResetFavoriteCourses will reset the current user's favorite courses to
+// the default set of courses.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.reset_course_favorites
+func (c *Canvas) ResetFavoriteCourses() error {
+	return resetFavorites(c.client, "courses")
+}
+
+// ResetFavoriteCourses will reset the current user's favorite courses to
+// the default set of courses.
+func ResetFavoriteCourses() error { return ca.ResetFavoriteCourses() }
+
+// ResetFavoriteGroups will reset the current user's favorite groups to
+// the default set of groups.
+//
+// https://canvas.instructure.com/doc/api/favorites.html#method.favorites.reset_groups_favorites
+func (c *Canvas) ResetFavoriteGroups() error {
+	return resetFavorites(c.client, "groups")
+}
+
+// ResetFavoriteGroups will reset the current user's favorite groups to
+// the default set of groups.
+func ResetFavoriteGroups() error { return ca.ResetFavoriteGroups() }
+
+// Favorite will add the course to the current user's favorites.
+func (c *Course) Favorite() error {
+	if _, err := favorite(c.client, "POST", "courses", c.ID); err != nil {
+		return err
+	}
+	c.IsFavorite = true
+	return nil
+}
+
+// Unfavorite will remove the course from the current user's favorites.
+func (c *Course) Unfavorite() error {
+	if _, err := favorite(c.client, "DELETE", "courses", c.ID); err != nil {
+		return err
+	}
+	c.IsFavorite = false
+	return nil
+}
+
+// Favorite will add the group to the current user's favorites.
+func (g *Group) Favorite() error {
+	_, err := favorite(g.client, "POST", "groups", g.ID)
+	return err
+}
+
+// Unfavorite will remove the group from the current user's favorites.
+func (g *Group) Unfavorite() error {
+	_, err := favorite(g.client, "DELETE", "groups", g.ID)
+	return err
+}
+
+func favoriteCourses(d doer, opts []Option) ([]*Course, error) {
+	courses, err := getCourses(d, "/users/self/favorites/courses", optEnc(opts))
+	for _, c := range courses {
+		c.IsFavorite = true
+	}
+	return courses, err
+}
+
+func favorite(d doer, method, typ string, id int) (*Favorite, error) {
+	req := newreq(method, fmt.Sprintf("/users/self/favorites/%s/%d", typ, id), nil)
+	resp, err := do(d, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	fav := &Favorite{}
+	return fav, json.NewDecoder(resp.Body).Decode(fav)
+}
+
+func resetFavorites(d doer, typ string) error {
+	resp, err := delete(d, "/users/self/favorites/"+typ, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
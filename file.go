@@ -1,15 +1,14 @@
 package canvas
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strconv"
 	"sync"
@@ -77,6 +76,13 @@ type File struct {
 	MediaEntryID  string `json:"media_entry_id"`
 	UploadStatus  string `json:"upload_status"`
 
+	// User is the user who uploaded the file. Only
+	// included when asking for IncludeOpt("user").
+	User *UserDisplay `json:"user"`
+	// UsageRights is the copyright and license information of the file.
+	// Only included when asking for IncludeOpt("usage_rights").
+	UsageRights *UsageRights `json:"usage_rights"`
+
 	client doer
 	folder *Folder
 }
@@ -198,25 +204,62 @@ func (f *File) edit(opts ...Option) error {
 	return json.NewDecoder(resp.Body).Decode(f)
 }
 
-// WriteTo will write the contents of the file to an io.Writer
+// WriteTo will write the contents of the file to an io.Writer. An error
+// wrapping ErrIncompleteDownload is returned if the number of bytes
+// written does not match the size of the file.
 func (f *File) WriteTo(w io.Writer) (int64, error) {
-	resp, err := http.Get(f.URL)
+	body, err := f.open(0)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
-	return io.Copy(w, resp.Body)
+	defer body.Close()
+	return io.Copy(w, body)
 }
 
 func (f *File) strID() string {
 	return strconv.FormatInt(int64(f.ID), 10)
 }
 
+// Replace will upload new contents for the file into the same folder
+// using the same name so that canvas overwrites the old file. Canvas keeps
+// links to the old file working even if it gives the new file a new id.
+// The file is updated in place with the new file's data.
+//
+// https://canvas.instructure.com/doc/api/file.file_uploads.html
+func (f *File) Replace(r io.Reader, opts ...Option) error {
+	if f.FolderID == 0 {
+		return errors.New("cannot replace file: file has no folder")
+	}
+	params := &fileUploadParams{Name: f.Name(), ParentFolderID: f.FolderID}
+	if params.Name == "" {
+		params.Name = f.Filename
+	}
+	params.setOptions(opts)
+	params.OnDuplicate = "overwrite"
+	file, err := uploadFile(f.client, r, fmt.Sprintf("/folders/%d/files", f.FolderID), params)
+	if err != nil {
+		return err
+	}
+	folder := f.folder
+	*f = *file
+	if folder != nil && folder.ID == f.FolderID {
+		f.folder = folder
+	}
+	return nil
+}
+
 // AsWriteCloser returns an io.WriteCloser that uploads
-// any data that has been written to it. All data
-// written will be sent to the file when the Close function
-// is called. Calling Close will also update the file that
-// is creating the WriteCloser.
+// any data that has been written to it. The data is streamed
+// to canvas as it is written and the upload is finished when
+// the Close function is called. Calling Close will also update
+// the file that is creating the WriteCloser.
+//
+// When the file has a parent folder, an existing file in that folder with
+// the same name is overwritten like File.Replace. Use Folder.UploadFile
+// with Opt("on_duplicate", "rename") to keep both files.
+//
+// Close must always be called, the upload request is left waiting for
+// more data until it is.
 //
 // This function may make an http request to find the parent folder.
 func (f *File) AsWriteCloser() (io.WriteCloser, error) {
@@ -226,41 +269,77 @@ func (f *File) AsWriteCloser() (io.WriteCloser, error) {
 	}
 	params := newFileUploadParams(f.Filename, nil)
 	parent, err := f.ParentFolder()
-	if err != nil && parent != nil {
+	if err == nil && parent != nil {
 		params.ParentFolderID = parent.ID
+		params.OnDuplicate = "overwrite"
 		if parent.ContextType != "" {
 			ctxPath := pathFromContextType(parent.ContextType)
 			path = fmt.Sprintf("%s/%d/files", ctxPath, parent.ContextID)
 		}
 	}
+	return newFileWriter(f.client, path, params, f), nil
+}
+
+func newFileWriter(d doer, path string, params *fileUploadParams, file *File) *fileWriter {
 	return &fileWriter{
-		buf:    new(bytes.Buffer),
-		params: params,
+		file:   file,
+		d:      d,
 		path:   path,
-		d:      f.client,
-		file:   f,
-	}, nil
+		params: params,
+		done:   make(chan struct{}),
+	}
 }
 
 type fileWriter struct {
 	file   *File
-	buf    *bytes.Buffer
-	params *fileUploadParams
-	path   string
 	d      doer
+	path   string
+	params *fileUploadParams
+
+	once   sync.Once
+	pw     *io.PipeWriter
+	done   chan struct{}
+	result *File
+	err    error
+}
+
+// start begins the upload. This waits until the first Write or
+// Close so that a writer that is never used does not start a
+// request that blocks forever.
+func (fw *fileWriter) start() {
+	fw.once.Do(func() {
+		pr, pw := io.Pipe()
+		fw.pw = pw
+		go func() {
+			defer close(fw.done)
+			fw.result, fw.err = uploadFile(fw.d, pr, fw.path, fw.params)
+			// unblock any writes if the upload stopped early
+			pr.CloseWithError(fw.err)
+		}()
+	})
 }
 
 func (fw *fileWriter) Write(b []byte) (int, error) {
-	return fw.buf.Write(b)
+	fw.start()
+	n, err := fw.pw.Write(b)
+	if err == io.ErrClosedPipe {
+		<-fw.done
+		if fw.err != nil {
+			err = fw.err
+		}
+	}
+	return n, err
 }
 
 func (fw *fileWriter) Close() error {
-	file, err := uploadFile(fw.d, fw.buf, fw.path, fw.params)
-	if err != nil {
-		return err
+	fw.start()
+	fw.pw.Close()
+	<-fw.done
+	if fw.err != nil {
+		return fw.err
 	}
 	if fw.file != nil {
-		*fw.file = *file
+		*fw.file = *fw.result
 	}
 	return nil
 }
@@ -269,11 +348,7 @@ func (fw *fileWriter) Close() error {
 //
 // This function will make an http request to get the data
 func (f *File) AsReadCloser() (io.ReadCloser, error) {
-	resp, err := http.Get(f.URL)
-	if err != nil {
-		return nil, err
-	}
-	return resp.Body, nil
+	return f.open(0)
 }
 
 // JoinFileObjs will join a file channel and a folder channel into a generic
@@ -421,7 +496,8 @@ func (f *Folder) CreateFolder(path string, opts ...Option) (*Folder, error) {
 	)
 }
 
-// Copy the folder to a another folder (dest)
+// Copy the folder to a another folder (dest). The whole
+// folder tree is copied including every file in it.
 // https://canvas.instructure.com/doc/api/files.html#method.folders.copy_folder
 func (f *Folder) Copy(dest *Folder) error {
 	resp, err := post(
@@ -565,9 +641,14 @@ type fileUploadParams struct {
 	ContentType      string `url:"content_type,omitempty"`
 	ParentFolderID   int    `url:"parent_folder_id,omitempty"`
 	ParentFolderPath string `url:"parent_folder_path,omitempty"`
+	// URL is a public url that canvas will download the file from
+	// instead of receiving the file contents.
+	URL string `url:"url,omitempty"`
 	// These will be set as if it were an "include[]" parameter
 	// when the upload returns a canvas file.
 	SuccessInclude []string `url:"success_include,omitempty"`
+
+	progress UploadProgressFunc
 }
 
 func (up *fileUploadParams) asOptions() []Option {
@@ -585,6 +666,10 @@ func (up *fileUploadParams) asOptions() []Option {
 func (up *fileUploadParams) setOptions(opts []Option) {
 	var vals []string
 	for _, opt := range opts {
+		if po, ok := opt.(*progressOpt); ok {
+			up.progress = po.fn
+			continue
+		}
 		vals = opt.Value()
 		if len(vals) < 1 {
 			continue
@@ -638,6 +723,24 @@ func newFileUploadParams(filename string, opts []Option) *fileUploadParams {
 	return p
 }
 
+// UploadProgressFunc is called as the contents of a file are sent to canvas.
+// Sent is the number of bytes sent so far and total is the size of the file
+// or -1 if the size is not known.
+type UploadProgressFunc func(sent, total int64)
+
+// UploadProgress returns an Option that will report the progress
+// of a file upload. It is ignored by everything but file uploads.
+func UploadProgress(fn UploadProgressFunc) Option {
+	return &progressOpt{fn: fn}
+}
+
+type progressOpt struct {
+	fn UploadProgressFunc
+}
+
+func (po *progressOpt) Name() string    { return "" }
+func (po *progressOpt) Value() []string { return nil }
+
 // https://canvas.instructure.com/doc/api/file.file_uploads.html
 func uploadFile(
 	d doer,
@@ -648,36 +751,63 @@ func uploadFile(
 	if params.Name == "" {
 		return nil, errors.New("empty filename")
 	}
+	// the size of the reader is used for the Content-Length so it
+	// is trusted over the size param which could be out of date
+	size := readerSize(r)
+	if size >= 0 {
+		params.Size = int(size)
+	} else if params.Size > 0 {
+		size = int64(params.Size)
+	}
+	if params.ContentType == "" {
+		var err error
+		params.ContentType, r, err = detectContentType(params.Name, r)
+		if err != nil {
+			return nil, err
+		}
+	}
 	req := newreq("POST", endpoint, params)
 	resp, err := do(d, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	uploader, err := decodeUploader(resp.Body) // will close the body
+	uploader, err := decodeUploader(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	return uploader.upload(d, params.Name, r)
+	if params.progress != nil {
+		r = &progressReader{r: r, total: size, fn: params.progress}
+	}
+	return uploader.upload(d, params.Name, r, size)
 }
 
-func decodeUploader(r io.Reader) (*fileupload, error) {
-	b := &bytes.Buffer{}
-	fup := &fileupload{
-		body:   b,
-		writer: multipart.NewWriter(b),
+// readerSize will find the number of bytes left in a reader
+// without reading it. Returns -1 if the size is not known.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }: // bytes.Buffer, bytes.Reader, strings.Reader
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
 	}
+	return -1
+}
+
+func decodeUploader(r io.Reader) (*fileupload, error) {
+	fup := &fileupload{}
 	err := json.NewDecoder(r).Decode(fup)
 	if err != nil {
 		return nil, err
 	}
-	for key, value := range fup.UploadParams {
-		if err = fup.writer.WriteField(key, value); err != nil {
-			// the canvas servers will reject the request if
-			// even one of the upload params is missing
-			return nil, err
-		}
-	}
 	fup.url, err = url.Parse(fup.UploadURL)
 	if err != nil {
 		return nil, err
@@ -691,27 +821,36 @@ type fileupload struct {
 	UploadURL    string            `json:"upload_url"`
 	UploadParams map[string]string `json:"upload_params"`
 
-	url    *url.URL
-	body   *bytes.Buffer
-	writer *multipart.Writer
+	url *url.URL
 }
 
-func (f *fileupload) upload(d doer, filename string, r io.Reader) (*File, error) {
-	form, err := f.writer.CreateFormFile(f.FileParam, filename)
-	if err != nil {
-		return nil, err
-	}
-	if _, err = io.Copy(form, r); err != nil {
-		return nil, err
+// upload streams the multipart body through a pipe so the file is never
+// held in memory. When the size is known the Content-Length is set,
+// otherwise the body is sent with chunked encoding.
+func (f *fileupload) upload(d doer, filename string, r io.Reader, size int64) (*File, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	writer := multipart.NewWriter(pw)
+
+	length := int64(-1)
+	if size >= 0 {
+		overhead, err := f.multipartLength(writer.Boundary(), filename)
+		if err != nil {
+			return nil, err
+		}
+		length = overhead + size
 	}
-	f.writer.Close() // do not defer, adds the correct line endings to the body
+	go func() {
+		pw.CloseWithError(f.writeBody(writer, filename, r))
+	}()
+
 	req := &http.Request{
 		Method: "POST",
 		URL:    f.url,
-		Body:   ioutil.NopCloser(f.body),
+		Body:   pr,
 		Header: http.Header{
-			"Content-Type": {f.writer.FormDataContentType()}},
-		ContentLength: int64(f.body.Len()),
+			"Content-Type": {writer.FormDataContentType()}},
+		ContentLength: length,
 	}
 	resp, err := do(d, req)
 	if err != nil {
@@ -722,15 +861,70 @@ func (f *fileupload) upload(d doer, filename string, r io.Reader) (*File, error)
 	return file, json.NewDecoder(resp.Body).Decode(file)
 }
 
+func (f *fileupload) writeBody(w *multipart.Writer, filename string, r io.Reader) error {
+	for key, value := range f.UploadParams {
+		// the canvas servers will reject the request if
+		// even one of the upload params is missing
+		if err := w.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+	form, err := w.CreateFormFile(f.FileParam, filename)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(form, r); err != nil {
+		return err
+	}
+	return w.Close() // adds the closing boundary
+}
+
+// multipartLength is the length of the multipart body without the file.
+func (f *fileupload) multipartLength(boundary, filename string) (int64, error) {
+	var c countWriter
+	w := multipart.NewWriter(&c)
+	if err := w.SetBoundary(boundary); err != nil {
+		return 0, err
+	}
+	err := f.writeBody(w, filename, eofReader{})
+	return int64(c), err
+}
+
+type countWriter int64
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	*c += countWriter(len(b))
+	return len(b), nil
+}
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+type progressReader struct {
+	r     io.Reader
+	sent  int64
+	total int64
+	fn    UploadProgressFunc
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	if n > 0 {
+		pr.sent += int64(n)
+		pr.fn(pr.sent, pr.total)
+	}
+	return n, err
+}
+
 func listFiles(d doer, path string, parent *Folder, opts []Option) ([]*File, error) {
 	if opts == nil {
 		opts = []Option{}
 	}
 	var (
-		page     = 1
-		perpage  = 10
-		files    []*File
-		tmpfiles []*File = make([]*File, 10)
+		page    = 1
+		perpage = 10
+		files   []*File
 	)
 	p := params{
 		"page":     {strconv.Itoa(page)},
@@ -748,6 +942,9 @@ func listFiles(d doer, path string, parent *Folder, opts []Option) ([]*File, err
 	}
 	files = make([]*File, 0, n*perpage)
 
+	// decode each page into a new slice so the
+	// pointers from earlier pages are not reused
+	var tmpfiles []*File
 	if err := json.NewDecoder(resp.Body).Decode(&tmpfiles); err != nil {
 		return nil, err
 	}
@@ -763,6 +960,7 @@ func listFiles(d doer, path string, parent *Folder, opts []Option) ([]*File, err
 		if err != nil {
 			return files, err
 		}
+		tmpfiles = nil
 		if err = json.NewDecoder(resp.Body).Decode(&tmpfiles); err != nil {
 			resp.Body.Close()
 			return files, err
@@ -46,6 +46,32 @@ func TestListFiles(t *testing.T) {
 	}
 }
 
+func TestListFiles_Pages(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		w.Header().Set("Link", `<https://canvas.instructure.com/api/v1/courses/1/files?page=1&per_page=10>; rel="first",`+
+			`<https://canvas.instructure.com/api/v1/courses/1/files?page=2&per_page=10>; rel="last"`)
+		switch page {
+		case "1":
+			w.Write([]byte(`[{"id":1},{"id":2}]`))
+		case "2":
+			w.Write([]byte(`[{"id":3},{"id":4}]`))
+		default:
+			t.Errorf("unexpected page %q", page)
+		}
+	})
+	c := &Course{ID: 1, client: client}
+	files, err := c.ListFiles()
+	is.NoErr(err)
+	is.Equal(len(files), 4)
+	for i, f := range files {
+		is.Equal(f.ID, i+1) // pages should not share file pointers
+	}
+}
+
 func TestFolders(t *testing.T) {
 	is := is.New(t)
 	folder := NewFolder("test")
@@ -0,0 +1,327 @@
+package canvas
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+)
+
+// FS is a read only file system over the files of a course, group or user.
+// Paths are slash separated and relative to the root folder, so "." is the
+// root folder itself. FS implements fs.FS, fs.ReadDirFS and fs.StatFS which
+// allows it to be used with fs.WalkDir, http.FS, template.ParseFS and so on.
+type FS struct {
+	client doer
+	path   string // context path, ex. "courses/1234"
+	root   *Folder
+}
+
+var (
+	_ fs.FS          = (*FS)(nil)
+	_ fs.ReadDirFS   = (*FS)(nil)
+	_ fs.StatFS      = (*FS)(nil)
+	_ fs.ReadDirFile = (*fsDir)(nil)
+	_ io.Seeker      = (*fsFile)(nil)
+	_ fs.DirEntry    = (*fileInfo)(nil)
+)
+
+// FS returns a file system over the course's files.
+func (c *Course) FS() *FS {
+	return &FS{client: c.client, path: c.id("courses/%d")}
+}
+
+// FS returns a file system over the group's files.
+func (g *Group) FS() *FS {
+	return &FS{client: g.client, path: g.id("groups/%d")}
+}
+
+// FS returns a file system over the user's files.
+func (u *User) FS() *FS {
+	return &FS{client: u.client, path: u.id("users/%d")}
+}
+
+// Open will open a file or folder. Opening a file does not download
+// it, the contents are only requested on the first call to Read.
+func (cfs *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	obj, err := cfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	switch o := obj.(type) {
+	case *Folder:
+		return &fsDir{folder: o, info: newFolderInfo(o, name)}, nil
+	case *File:
+		return &fsFile{file: o, info: newFileInfo(o)}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat returns the fs.FileInfo of a file or folder. The Sys method
+// of the FileInfo returns the underlying *File or *Folder.
+func (cfs *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	obj, err := cfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if f, ok := obj.(*Folder); ok {
+		return newFolderInfo(f, name), nil
+	}
+	return newFileInfo(obj.(*File)), nil
+}
+
+// ReadDir will read the folder and return its contents sorted by name.
+func (cfs *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	obj, err := cfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	folder, ok := obj.(*Folder)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fmt.Errorf("not a directory")}
+	}
+	entries, err := readFolder(folder)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+func (cfs *FS) lookup(name string) (FileObj, error) {
+	if name == "." {
+		return cfs.rootFolder()
+	}
+	folders, err := folderList(cfs.client, path.Join("/", cfs.path, "folders/by_path", name))
+	if err == nil && len(folders) > 0 {
+		last := folders[len(folders)-1]
+		if last.Name() == path.Base(name) {
+			return last, nil
+		}
+	} else if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	// not a folder so look for a file in the parent folder
+	parent, err := cfs.lookup(path.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := parent.(*Folder)
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	files, err := dir.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(name)
+	for _, f := range files {
+		if f.Name() == base {
+			return f, nil
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+func (cfs *FS) rootFolder() (*Folder, error) {
+	if cfs.root != nil {
+		return cfs.root, nil
+	}
+	f := &Folder{client: cfs.client}
+	if err := getjson(cfs.client, f, nil, "/%s/folders/root", cfs.path); err != nil {
+		return nil, err
+	}
+	cfs.root = f
+	return f, nil
+}
+
+// isNotFound reports whether the error is what canvas sends back
+// for resources that do not exist. Other auth errors like an
+// expired token are not.
+func isNotFound(err error) bool {
+	ae, ok := err.(*AuthError)
+	return ok && ae.code == http.StatusNotFound
+}
+
+func readFolder(folder *Folder) ([]fs.DirEntry, error) {
+	folders, err := folder.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := folder.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, 0, len(folders)+len(files))
+	for _, f := range folders {
+		entries = append(entries, newFolderInfo(f, f.Name()))
+	}
+	for _, f := range files {
+		entries = append(entries, newFileInfo(f))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+type fsFile struct {
+	file   *File
+	info   *fileInfo
+	body   io.ReadCloser
+	offset int64
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *fsFile) Read(b []byte) (int, error) {
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		body, err := f.file.open(f.offset)
+		if err != nil {
+			return 0, err
+		}
+		if body.n < f.offset {
+			// the server does not support ranges
+			if _, err = io.CopyN(ioutil.Discard, body, f.offset-body.n); err != nil {
+				body.Close()
+				return 0, err
+			}
+		}
+		f.body = body
+	}
+	n, err := f.body.Read(b)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek will set the offset of the next Read. Seeking to a new
+// offset will request the file again starting at that offset.
+func (f *fsFile) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = f.offset + offset
+	case io.SeekEnd:
+		abs = f.info.size + offset
+	default:
+		return 0, fmt.Errorf("seek: invalid whence %d", whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("seek: negative position")
+	}
+	if abs != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = abs
+	return abs, nil
+}
+
+func (f *fsFile) Close() error {
+	if f.body == nil {
+		return nil
+	}
+	err := f.body.Close()
+	f.body = nil
+	return err
+}
+
+type fsDir struct {
+	folder  *Folder
+	info    *fileInfo
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fmt.Errorf("is a directory")}
+}
+
+func (d *fsDir) Close() error { return nil }
+
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := readFolder(d.folder)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// fileInfo implements both fs.FileInfo and fs.DirEntry.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+	sys     FileObj
+}
+
+func newFileInfo(f *File) *fileInfo {
+	return &fileInfo{
+		name:    f.Name(),
+		size:    int64(f.Size),
+		modTime: f.UpdatedAt,
+		sys:     f,
+	}
+}
+
+func newFolderInfo(f *Folder, name string) *fileInfo {
+	return &fileInfo{
+		name:    path.Base(name),
+		modTime: f.UpdatedAt,
+		dir:     true,
+		sys:     f,
+	}
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return fi.sys }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (fi *fileInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi *fileInfo) Info() (fs.FileInfo, error) { return fi, nil }
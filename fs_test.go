@@ -0,0 +1,147 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFS(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	root := `{"id":1,"full_name":"course files","name":"course files"}`
+	notes := `{"id":2,"parent_folder_id":1,"full_name":"course files/notes","name":"notes"}`
+	mux.HandleFunc("/api/v1/courses/1/folders/root", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(root))
+	})
+	mux.HandleFunc("/api/v1/courses/1/folders/by_path/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/courses/1/folders/by_path/notes":
+			fmt.Fprintf(w, "[%s,%s]", root, notes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"The specified resource does not exist."}]}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/folders/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		fmt.Fprintf(w, "[%s]", notes)
+	})
+	mux.HandleFunc("/api/v1/folders/2/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte("[]"))
+	})
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		fmt.Fprintf(w, `[{"id":10,"folder_id":1,"display_name":"syllabus.txt","size":8,"url":"%s/download/10"}]`, server.URL)
+	})
+	mux.HandleFunc("/api/v1/folders/2/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		fmt.Fprintf(w, `[{"id":11,"folder_id":2,"display_name":"week1.txt","size":5,"url":"%s/download/11"}]`, server.URL)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/download/10":
+			w.Write([]byte("syllabus"))
+		case "/download/11":
+			w.Write([]byte("week1"))
+		}
+	})
+
+	fsys := (&Course{ID: 1, client: client}).FS()
+	var walked []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, p)
+		return nil
+	})
+	is.NoErr(err)
+	is.Equal(walked, []string{".", "notes", "notes/week1.txt", "syllabus.txt"})
+
+	b, err := fs.ReadFile(fsys, "notes/week1.txt")
+	is.NoErr(err)
+	is.Equal(string(b), "week1")
+
+	info, err := fs.Stat(fsys, "notes")
+	is.NoErr(err)
+	is.True(info.IsDir())
+	is.Equal(info.Sys().(*Folder).ID, 2)
+	info, err = fs.Stat(fsys, "syllabus.txt")
+	is.NoErr(err)
+	is.Equal(info.Size(), int64(8))
+	is.Equal(info.Mode(), fs.FileMode(0444))
+
+	_, err = fsys.Open("notes/missing.txt")
+	is.True(errors.Is(err, fs.ErrNotExist))
+	_, err = fsys.Open("../secrets")
+	is.True(errors.Is(err, fs.ErrInvalid))
+
+	// http.FileServer needs Seek for range requests
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/syllabus.txt", nil)
+	req.Header.Set("Range", "bytes=4-")
+	http.FileServer(http.FS(fsys)).ServeHTTP(rec, req)
+	is.Equal(rec.Code, http.StatusPartialContent)
+	body, err := ioutil.ReadAll(rec.Body)
+	is.NoErr(err)
+	is.Equal(string(body), "abus")
+}
+
+func TestFS_AuthError(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/courses/1/folders/root", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"full_name":"course files","name":"course files"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/folders/by_path/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status":"unauthenticated","errors":[{"message":"Invalid access token."}]}`))
+	})
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":10,"folder_id":1,"display_name":"syllabus.txt","size":8}]`))
+	})
+
+	fsys := (&Course{ID: 1, client: client}).FS()
+	_, err := fs.Stat(fsys, "syllabus.txt")
+	is.True(err != nil)
+	is.True(!errors.Is(err, fs.ErrNotExist)) // an expired token is not a missing file
+	var autherr *AuthError
+	is.True(errors.As(err, &autherr))
+}
+
+func TestFS_NotFoundBody(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/courses/1/folders/root", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"full_name":"course files","name":"course files"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/folders/by_path/", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r) // not a json body
+	})
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":10,"folder_id":1,"display_name":"syllabus.txt","size":8}]`))
+	})
+
+	fsys := (&Course{ID: 1, client: client}).FS()
+	info, err := fs.Stat(fsys, "syllabus.txt")
+	is.NoErr(err)
+	is.Equal(info.Size(), int64(8))
+	_, err = fs.Stat(fsys, "missing.txt")
+	is.True(errors.Is(err, fs.ErrNotExist))
+}
@@ -1,6 +1,6 @@
 module github.com/ArchWizard56/go-canvas
 
-go 1.14
+go 1.16
 
 require (
 	github.com/harrybrwn/errs v0.0.2-0.20200523142445-e4279967174e
@@ -0,0 +1,530 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+)
+
+// GroupCategory is a group category, it holds a set of groups.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html
+type GroupCategory struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Role               string `json:"role"`
+	SelfSignup         string `json:"self_signup"`
+	AutoLeader         string `json:"auto_leader"`
+	ContextType        string `json:"context_type"`
+	AccountID          int    `json:"account_id"`
+	CourseID           int    `json:"course_id"`
+	GroupLimit         int    `json:"group_limit"`
+	SisGroupCategoryID string `json:"sis_group_category_id"`
+	SisImportID        int    `json:"sis_import_id"`
+
+	client doer
+}
+
+// Groups will list the groups in the group category.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.groups
+func (gc *GroupCategory) Groups(opts ...Option) ([]*Group, error) {
+	return listGroups(gc.client, gc.id("/group_categories/%d/groups"), opts)
+}
+
+// CreateGroup will create a new group in the group category.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.create
+func (gc *GroupCategory) CreateGroup(name string, opts ...Option) (*Group, error) {
+	return createGroup(gc.client, gc.id("/group_categories/%d/groups"), name, opts)
+}
+
+// Users will list the users in the group category.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.users
+func (gc *GroupCategory) Users(opts ...Option) ([]*User, error) {
+	return listUsers(gc.client, gc.id("/group_categories/%d/users"), opts)
+}
+
+// UnassignedUsers will list the users in the group category's context
+// that are not in any of its groups.
+func (gc *GroupCategory) UnassignedUsers(opts ...Option) ([]*User, error) {
+	opts = append(opts, Opt("unassigned", true))
+	return gc.Users(opts...)
+}
+
+// AssignUnassigned will assign all of the unassigned users to groups in
+// the category and return the users that were added to each group. The
+// assignment is done synchronously.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.assign_unassigned_members
+func (gc *GroupCategory) AssignUnassigned(opts ...Option) ([]*GroupAssignment, error) {
+	p := params{"sync": {"true"}}
+	p.Add(opts)
+	resp, err := post(gc.client, gc.id("/group_categories/%d/assign_unassigned_members"), p)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	assignments := make([]*GroupAssignment, 0)
+	if err = json.NewDecoder(resp.Body).Decode(&assignments); err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		for _, u := range a.NewMembers {
+			u.setclient(gc.client)
+		}
+	}
+	return assignments, nil
+}
+
+// GroupAssignment is a group and the users that were
+// added to it by GroupCategory.AssignUnassigned.
+type GroupAssignment struct {
+	GroupID    int
+	NewMembers []*User
+}
+
+// UnmarshalJSON decodes a group assignment. Canvas sends the new members
+// with a "user_id" instead of an "id".
+func (ga *GroupAssignment) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID         int `json:"id"`
+		NewMembers []struct {
+			UserID       int    `json:"user_id"`
+			Name         string `json:"name"`
+			SortableName string `json:"sortable_name"`
+			ShortName    string `json:"short_name"`
+			SisUserID    string `json:"sis_user_id"`
+			LoginID      string `json:"login_id"`
+		} `json:"new_members"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	ga.GroupID = raw.ID
+	ga.NewMembers = make([]*User, len(raw.NewMembers))
+	for i, m := range raw.NewMembers {
+		ga.NewMembers[i] = &User{
+			ID:           m.UserID,
+			Name:         m.Name,
+			SortableName: m.SortableName,
+			ShortName:    m.ShortName,
+			SisUserID:    m.SisUserID,
+			LoginID:      m.LoginID,
+		}
+	}
+	return nil
+}
+
+// Update will update the group category.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.update
+func (gc *GroupCategory) Update(opts ...Option) error {
+	resp, err := put(gc.client, gc.id("/group_categories/%d"), optEnc(opts))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(gc)
+}
+
+// Delete will delete the group category.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.destroy
+func (gc *GroupCategory) Delete() error {
+	resp, err := delete(gc.client, gc.id("/group_categories/%d"), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (gc *GroupCategory) id(s string) string {
+	return fmt.Sprintf(s, gc.ID)
+}
+
+// Group is a canvas group.
+//
+// https://canvas.instructure.com/doc/api/groups.html
+type Group struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	IsPublic        bool   `json:"is_public"`
+	FollowedByUser  bool   `json:"followed_by_user"`
+	JoinLevel       string `json:"join_level"`
+	MembersCount    int    `json:"members_count"`
+	AvatarURL       string `json:"avatar_url"`
+	ContextType     string `json:"context_type"`
+	CourseID        int    `json:"course_id"`
+	AccountID       int    `json:"account_id"`
+	Role            string `json:"role"`
+	GroupCategoryID int    `json:"group_category_id"`
+	SisGroupID      string `json:"sis_group_id"`
+	SisImportID     int    `json:"sis_import_id"`
+	StorageQuotaMb  int    `json:"storage_quota_mb"`
+	Permissions     struct {
+		CreateDiscussionTopic bool `json:"create_discussion_topic"`
+		CreateAnnouncement    bool `json:"create_announcement"`
+	} `json:"permissions"`
+	Users []*User `json:"users"`
+
+	client doer
+}
+
+// ContextCode returns the context code for the group.
+func (g *Group) ContextCode() string {
+	return fmt.Sprintf("group_%d", g.ID)
+}
+
+// Update will update the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.update
+func (g *Group) Update(opts ...Option) error {
+	resp, err := put(g.client, g.id("/groups/%d"), optEnc(opts))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(g)
+}
+
+// Delete will delete the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.destroy
+func (g *Group) Delete() error {
+	resp, err := delete(g.client, g.id("/groups/%d"), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListUsers will list the users in the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.users
+func (g *Group) ListUsers(opts ...Option) ([]*User, error) {
+	return listUsers(g.client, g.id("/groups/%d/users"), opts)
+}
+
+// Memberships will list the group's memberships.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.group_memberships.index
+func (g *Group) Memberships(opts ...Option) (mems []*GroupMembership, err error) {
+	ch := make(chan *GroupMembership)
+	pager := newPaginatedList(
+		g.client, g.id("/groups/%d/memberships"),
+		func(r io.Reader) error {
+			list := make([]*GroupMembership, 0, defaultPerPage)
+			if err := json.NewDecoder(r).Decode(&list); err != nil {
+				return err
+			}
+			for _, m := range list {
+				m.client = g.client
+				ch <- m
+			}
+			return nil
+		}, opts,
+	)
+	errs := pager.start()
+	for {
+		select {
+		case m := <-ch:
+			mems = append(mems, m)
+		case err := <-errs:
+			return mems, err
+		}
+	}
+}
+
+// AddMember will add a user to the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.group_memberships.create
+func (g *Group) AddMember(userID int) (*GroupMembership, error) {
+	resp, err := post(
+		g.client, g.id("/groups/%d/memberships"),
+		params{"user_id": {strconv.Itoa(userID)}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	m := &GroupMembership{client: g.client}
+	return m, json.NewDecoder(resp.Body).Decode(m)
+}
+
+// RemoveMember will remove a user from the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.group_memberships.destroy
+func (g *Group) RemoveMember(userID int) error {
+	resp, err := delete(g.client, fmt.Sprintf("/groups/%d/users/%d", g.ID, userID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Invite will invite users to the group by email.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.invite
+func (g *Group) Invite(emails ...string) error {
+	resp, err := post(g.client, g.id("/groups/%d/invite"), params{"invitees[]": emails})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// DiscussionTopics return a list of the group's discussion topics.
+func (g *Group) DiscussionTopics(opts ...Option) ([]*DiscussionTopic, error) {
+	return listDiscussionTopics(g.client, g.id("groups/%d"), opts)
+}
+
+// Files returns a channel of all the group's files.
+func (g *Group) Files(opts ...Option) <-chan *File {
+	return filesChannel(g.client, g.id("/groups/%d/files"), ConcurrentErrorHandler, opts, nil)
+}
+
+// File will get a specific file from the group.
+func (g *Group) File(id int, opts ...Option) (*File, error) {
+	f := &File{client: g.client}
+	return f, getjson(g.client, f, optEnc(opts), "groups/%d/files/%d", g.ID, id)
+}
+
+// ListFiles returns a slice of files for the group.
+func (g *Group) ListFiles(opts ...Option) ([]*File, error) {
+	return listFiles(g.client, g.id("/groups/%d/files"), nil, opts)
+}
+
+// Folders will return a channel of the group's folders.
+func (g *Group) Folders(opts ...Option) <-chan *Folder {
+	return foldersChannel(
+		g.client, g.id("/groups/%d/folders"),
+		ConcurrentErrorHandler, opts, nil,
+	)
+}
+
+// ListFolders returns a slice of folders for the group.
+func (g *Group) ListFolders(opts ...Option) ([]*Folder, error) {
+	return listFolders(g.client, g.id("/groups/%d/folders"), nil, opts)
+}
+
+// Root will get the root folder for the group's files.
+func (g *Group) Root(opts ...Option) (*Folder, error) {
+	f := &Folder{client: g.client}
+	return f, getjson(g.client, f, optEnc(opts), "/groups/%d/folders/root", g.ID)
+}
+
+// FolderPath will split the path and return a list containing
+// all of the folders in the path.
+func (g *Group) FolderPath(pth string) ([]*Folder, error) {
+	pth = path.Join(g.id("/groups/%d/folders/by_path"), pth)
+	return folderList(g.client, pth)
+}
+
+// CreateFolder will create a new folder in the group's files.
+func (g *Group) CreateFolder(path string, opts ...Option) (*Folder, error) {
+	dir, name := filepath.Split(path)
+	return createFolder(g.client, dir, name, opts, "/groups/%d/folders", g.ID)
+}
+
+// UploadFile will upload a file to the group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.create_file
+func (g *Group) UploadFile(filename string, r io.Reader, opts ...Option) (*File, error) {
+	return uploadFile(
+		g.client, r, g.id("/groups/%d/files"),
+		newFileUploadParams(filename, opts),
+	)
+}
+
+func (g *Group) id(s string) string {
+	return fmt.Sprintf(s, g.ID)
+}
+
+// GroupMembership is a user's membership in a group.
+type GroupMembership struct {
+	ID            int    `json:"id"`
+	GroupID       int    `json:"group_id"`
+	UserID        int    `json:"user_id"`
+	WorkflowState string `json:"workflow_state"`
+	Moderator     bool   `json:"moderator"`
+	JustCreated   bool   `json:"just_created"`
+	SisImportID   int    `json:"sis_import_id"`
+
+	client doer
+}
+
+// Accept will accept a membership request or invitation.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.group_memberships.update
+func (m *GroupMembership) Accept() error {
+	return m.update(Opt("workflow_state", "accepted"))
+}
+
+// SetModerator will set the membership's moderator status.
+func (m *GroupMembership) SetModerator(moderator bool) error {
+	return m.update(Opt("moderator", moderator))
+}
+
+func (m *GroupMembership) update(opts ...Option) error {
+	resp, err := put(
+		m.client,
+		fmt.Sprintf("/groups/%d/memberships/%d", m.GroupID, m.ID),
+		optEnc(opts),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(m)
+}
+
+// GroupCategories will list the course's group categories.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.index
+func (c *Course) GroupCategories(opts ...Option) ([]*GroupCategory, error) {
+	return listGroupCategories(c.client, c.id("/courses/%d/group_categories"), opts)
+}
+
+// CreateGroupCategory will create a new group category in the course.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.create
+func (c *Course) CreateGroupCategory(name string, opts ...Option) (*GroupCategory, error) {
+	q := params{"name": {name}}
+	q.Add(opts)
+	resp, err := post(c.client, c.id("/courses/%d/group_categories"), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	gc := &GroupCategory{client: c.client}
+	return gc, json.NewDecoder(resp.Body).Decode(gc)
+}
+
+// Groups will list the course's groups.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.context_index
+func (c *Course) Groups(opts ...Option) ([]*Group, error) {
+	return listGroups(c.client, c.id("/courses/%d/groups"), opts)
+}
+
+// GroupCategories will list the account's group categories.
+func (a *Account) GroupCategories(opts ...Option) ([]*GroupCategory, error) {
+	return listGroupCategories(a.cli, fmt.Sprintf("/accounts/%d/group_categories", a.ID), opts)
+}
+
+// Groups will list the account's groups.
+func (a *Account) Groups(opts ...Option) ([]*Group, error) {
+	return listGroups(a.cli, fmt.Sprintf("/accounts/%d/groups", a.ID), opts)
+}
+
+// Groups will list the current user's groups.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.index
+func (c *Canvas) Groups(opts ...Option) ([]*Group, error) {
+	return listGroups(c.client, "/users/self/groups", opts)
+}
+
+// Groups will list the current user's groups.
+func Groups(opts ...Option) ([]*Group, error) { return ca.Groups(opts...) }
+
+// GetGroup will get a group given its id.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.show
+func (c *Canvas) GetGroup(id int, opts ...Option) (*Group, error) {
+	g := &Group{client: c.client}
+	return g, getjson(c.client, g, optEnc(opts), "/groups/%d", id)
+}
+
+// GetGroup will get a group given its id.
+func GetGroup(id int, opts ...Option) (*Group, error) { return ca.GetGroup(id, opts...) }
+
+// CreateGroup will create a new community group.
+//
+// https://canvas.instructure.com/doc/api/groups.html#method.groups.create
+func (c *Canvas) CreateGroup(name string, opts ...Option) (*Group, error) {
+	return createGroup(c.client, "/groups", name, opts)
+}
+
+// CreateGroup will create a new community group.
+func CreateGroup(name string, opts ...Option) (*Group, error) {
+	return ca.CreateGroup(name, opts...)
+}
+
+// GetGroupCategory will get a group category given its id.
+//
+// https://canvas.instructure.com/doc/api/group_categories.html#method.group_categories.show
+func (c *Canvas) GetGroupCategory(id int, opts ...Option) (*GroupCategory, error) {
+	gc := &GroupCategory{client: c.client}
+	return gc, getjson(c.client, gc, optEnc(opts), "/group_categories/%d", id)
+}
+
+// GetGroupCategory will get a group category given its id.
+func GetGroupCategory(id int, opts ...Option) (*GroupCategory, error) {
+	return ca.GetGroupCategory(id, opts...)
+}
+
+func createGroup(d doer, path, name string, opts []Option) (*Group, error) {
+	q := params{"name": {name}}
+	q.Add(opts)
+	resp, err := post(d, path, q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	g := &Group{client: d}
+	return g, json.NewDecoder(resp.Body).Decode(g)
+}
+
+func listGroups(d doer, path string, opts []Option) (groups []*Group, err error) {
+	ch := make(chan *Group)
+	pager := newPaginatedList(d, path, func(r io.Reader) error {
+		list := make([]*Group, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, g := range list {
+			g.client = d
+			ch <- g
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case g := <-ch:
+			groups = append(groups, g)
+		case err := <-errs:
+			return groups, err
+		}
+	}
+}
+
+func listGroupCategories(d doer, path string, opts []Option) (cats []*GroupCategory, err error) {
+	ch := make(chan *GroupCategory)
+	pager := newPaginatedList(d, path, func(r io.Reader) error {
+		list := make([]*GroupCategory, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, gc := range list {
+			gc.client = d
+			ch <- gc
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case gc := <-ch:
+			cats = append(cats, gc)
+		case err := <-errs:
+			return cats, err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+const testLinkHeader = `<https://canvas.instructure.com/api/v1/path?page=1&per_page=10>; rel="current",<https://canvas.instructure.com/api/v1/path?page=1&per_page=10>; rel="first",<https://canvas.instructure.com/api/v1/path?page=1&per_page=10>; rel="last"`
+
+func TestGroups(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	mux.HandleFunc("/api/v1/courses/1/group_categories", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("name"), "Project Groups")
+		is.Equal(r.URL.Query().Get("create_group_count"), "2")
+		w.Write([]byte(`{"id":10,"name":"Project Groups","course_id":1}`))
+	})
+	mux.HandleFunc("/api/v1/group_categories/10/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":20,"name":"one","group_category_id":10},{"id":21,"name":"two","group_category_id":10}]`))
+	})
+	mux.HandleFunc("/api/v1/group_categories/10/assign_unassigned_members", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("sync"), "true")
+		w.Write([]byte(`[{"id":20,"new_members":[` +
+			`{"user_id":2,"name":"Sheldon Cooper","sortable_name":"Cooper, Sheldon","short_name":"Shelly"},` +
+			`{"user_id":3,"name":"Leonard Hofstadter","sortable_name":"Hofstadter, Leonard","short_name":"Leonard"}]}]`))
+	})
+	mux.HandleFunc("/api/v1/groups/20/memberships", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("user_id"), "2")
+		w.Write([]byte(`{"id":30,"group_id":20,"user_id":2,"workflow_state":"invited"}`))
+	})
+	mux.HandleFunc("/api/v1/groups/20/memberships/30", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		is.Equal(r.URL.Query().Get("workflow_state"), "accepted")
+		w.Write([]byte(`{"id":30,"group_id":20,"user_id":2,"workflow_state":"accepted"}`))
+	})
+	mux.HandleFunc("/api/v1/groups/20/folders", handlePagingatedList(t, 2, "folder.json"))
+
+	course := &Course{client: client, ID: 1}
+	cat, err := course.CreateGroupCategory("Project Groups", Opt("create_group_count", 2))
+	is.NoErr(err)
+	is.Equal(cat.ID, 10)
+	groups, err := cat.Groups()
+	is.NoErr(err)
+	is.Equal(len(groups), 2)
+	changed, err := cat.AssignUnassigned()
+	is.NoErr(err)
+	is.Equal(len(changed), 1)
+	is.Equal(changed[0].GroupID, 20)
+	is.Equal(len(changed[0].NewMembers), 2)
+	is.Equal(changed[0].NewMembers[0].ID, 2)
+	is.Equal(changed[0].NewMembers[1].ShortName, "Leonard")
+	is.True(changed[0].NewMembers[0].client != nil)
+
+	g := groups[0]
+	is.True(g.client != nil)
+	is.Equal(g.ContextCode(), "group_20")
+	m, err := g.AddMember(2)
+	is.NoErr(err)
+	is.NoErr(m.Accept())
+	is.Equal(m.WorkflowState, "accepted")
+
+	folders, err := g.ListFolders()
+	is.NoErr(err)
+	is.Equal(len(folders), 2)
+}
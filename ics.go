@@ -0,0 +1,515 @@
+package canvas
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateFormat     = "20060102"
+	icsDateTimeFormat = "20060102T150405"
+	icsLineLength     = 75
+)
+
+// WriteICS will write the calendar events to w as an iCalendar (RFC 5545)
+// file. The timezone is the name of an IANA time zone, such as
+// Account.DefaultTimeZone or User.TimeZone, and is used for the times of
+// each event. UTC is used if the timezone is empty.
+func WriteICS(w io.Writer, events []*CalendarEvent, timezone string) error {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return err
+		}
+	}
+	iw := &icsWriter{w: bufio.NewWriter(w)}
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//go-canvas//Canvas Calendar//EN")
+	iw.line("CALSCALE:GREGORIAN")
+	if loc != time.UTC {
+		iw.line("X-WR-TIMEZONE:" + loc.String())
+		writeVTimezone(iw, loc, events)
+	}
+	for i, e := range events {
+		writeVEvent(iw, e, loc, i)
+	}
+	iw.line("END:VCALENDAR")
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
+
+// WriteICS will write the calendar events to w as an iCalendar file
+// using the account's default time zone.
+func (a *Account) WriteICS(w io.Writer, events []*CalendarEvent) error {
+	return WriteICS(w, events, a.DefaultTimeZone)
+}
+
+// WriteICS will write the calendar events to w as an iCalendar file
+// using the user's time zone.
+func (u *User) WriteICS(w io.Writer, events []*CalendarEvent) error {
+	return WriteICS(w, events, u.TimeZone)
+}
+
+// ParseICS will read an iCalendar (RFC 5545) file and return its events.
+// The events will not have a ContextCode set so one must be added before
+// passing them to CreateCalendarEvent. Recurrence rules with a daily,
+// weekly, or monthly frequency and a count are converted to the event's
+// Duplicate field.
+func ParseICS(r io.Reader) ([]*CalendarEvent, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		events   []*CalendarEvent
+		event    *CalendarEvent
+		duration *time.Duration // applied once DTSTART is known
+		zones    = make(map[string]*time.Location)
+		tzid     string
+		inZone   bool
+		offsets  = make(map[string]int)
+	)
+	for _, l := range lines {
+		name, params, value := splitICSLine(l)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event, duration = &CalendarEvent{}, nil
+		case name == "END" && value == "VEVENT":
+			if event != nil {
+				if duration != nil {
+					event.EndAt = event.StartAt.Add(*duration)
+				}
+				events = append(events, event)
+			}
+			event = nil
+		case name == "BEGIN" && value == "VTIMEZONE":
+			inZone = true
+		case name == "END" && value == "VTIMEZONE":
+			// fall back to a fixed offset when the time zone is not
+			// an IANA name that can be loaded
+			if _, err := time.LoadLocation(tzid); err != nil {
+				zones[tzid] = time.FixedZone(tzid, offsets[tzid])
+			}
+			inZone = false
+		case inZone && name == "TZID":
+			tzid = value
+		case inZone && name == "TZOFFSETTO":
+			if off, err := parseUTCOffset(value); err == nil {
+				offsets[tzid] = off
+			}
+		case event != nil && name == "DURATION":
+			d, err := parseICSDuration(value)
+			if err != nil {
+				return nil, err
+			}
+			duration = &d
+		case event != nil:
+			if err = setICSProperty(event, name, params, value, zones); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return events, nil
+}
+
+// ImportICS will parse an iCalendar file and create each
+// event in the context given, ex. "course_1234".
+func (c *Canvas) ImportICS(r io.Reader, contextCode string) ([]*CalendarEvent, error) {
+	events, err := ParseICS(r)
+	if err != nil {
+		return nil, err
+	}
+	created := make([]*CalendarEvent, 0, len(events))
+	for _, e := range events {
+		e.ContextCode = contextCode
+		ev, err := c.CreateCalendarEvent(e)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, ev)
+	}
+	return created, nil
+}
+
+// ImportICS will parse an iCalendar file and create each
+// event in the context given, ex. "course_1234".
+func ImportICS(r io.Reader, contextCode string) ([]*CalendarEvent, error) {
+	return ca.ImportICS(r, contextCode)
+}
+
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line folding it at 75 octets.
+func (iw *icsWriter) line(l string) {
+	if iw.err != nil {
+		return
+	}
+	limit := icsLineLength
+	for len(l) > limit {
+		n := limit
+		// don't split a multi-byte character
+		for n > 0 && l[n]&0xC0 == 0x80 {
+			n--
+		}
+		if _, iw.err = iw.w.WriteString(l[:n] + "\r\n "); iw.err != nil {
+			return
+		}
+		l = l[n:]
+		limit = icsLineLength - 1 // leave room for the leading space
+	}
+	_, iw.err = iw.w.WriteString(l + "\r\n")
+}
+
+func writeVEvent(iw *icsWriter, e *CalendarEvent, loc *time.Location, i int) {
+	iw.line("BEGIN:VEVENT")
+	if e.ID != 0 {
+		iw.line(fmt.Sprintf("UID:canvas-calendar-event-%d", e.ID))
+	} else {
+		iw.line(fmt.Sprintf("UID:canvas-calendar-event-%d-%d", e.StartAt.Unix(), i))
+	}
+	stamp := e.UpdatedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	iw.line("DTSTAMP:" + stamp.UTC().Format(icsDateTimeFormat) + "Z")
+	if e.AllDay {
+		start := e.StartAt.In(loc)
+		if d, err := time.Parse("2006-01-02", e.AllDayDate); err == nil {
+			start = d
+		}
+		iw.line("DTSTART;VALUE=DATE:" + start.Format(icsDateFormat))
+		iw.line("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format(icsDateFormat))
+	} else {
+		iw.line("DTSTART" + icsTime(e.StartAt, loc))
+		if !e.EndAt.IsZero() {
+			iw.line("DTEND" + icsTime(e.EndAt, loc))
+		}
+	}
+	iw.line("SUMMARY:" + escapeICS(e.Title))
+	if e.Description != "" {
+		iw.line("DESCRIPTION:" + escapeICS(e.Description))
+	}
+	location := e.LocationName
+	if e.LocationAddress != "" {
+		if location != "" {
+			location += ", "
+		}
+		location += e.LocationAddress
+	}
+	if location != "" {
+		iw.line("LOCATION:" + escapeICS(location))
+	}
+	if e.HTMLURL != "" {
+		iw.line("URL:" + e.HTMLURL)
+	}
+	iw.line("END:VEVENT")
+}
+
+func icsTime(t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return ":" + t.UTC().Format(icsDateTimeFormat) + "Z"
+	}
+	return fmt.Sprintf(";TZID=%s:%s", loc, t.In(loc).Format(icsDateTimeFormat))
+}
+
+// writeVTimezone writes a VTIMEZONE component with an observance for
+// every offset change in the years covered by the events.
+func writeVTimezone(iw *icsWriter, loc *time.Location, events []*CalendarEvent) {
+	first, last := 0, 0
+	for _, e := range events {
+		if e.StartAt.IsZero() {
+			continue
+		}
+		y := e.StartAt.Year()
+		if first == 0 || y < first {
+			first = y
+		}
+		if y > last {
+			last = y
+		}
+	}
+	if first == 0 {
+		first, last = time.Now().Year(), time.Now().Year()
+	}
+	iw.line("BEGIN:VTIMEZONE")
+	iw.line("TZID:" + loc.String())
+	start := time.Date(first, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(last+1, time.January, 1, 0, 0, 0, 0, loc)
+	_, prev := start.Zone()
+	n := 0
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		name, off := t.Zone()
+		if off == prev {
+			continue
+		}
+		writeObservance(iw, t, name, prev, off)
+		prev = off
+		n++
+	}
+	if n == 0 {
+		name, off := start.Zone()
+		writeObservance(iw, time.Date(1970, time.January, 1, 0, 0, 0, 0, loc), name, off, off)
+	}
+	iw.line("END:VTIMEZONE")
+}
+
+func writeObservance(iw *icsWriter, t time.Time, name string, from, to int) {
+	kind := "STANDARD"
+	if to > from {
+		kind = "DAYLIGHT"
+	}
+	iw.line("BEGIN:" + kind)
+	// DTSTART of an observance is the local time before the change
+	iw.line("DTSTART:" + t.UTC().Add(time.Duration(from)*time.Second).Format(icsDateTimeFormat))
+	iw.line("TZOFFSETFROM:" + formatUTCOffset(from))
+	iw.line("TZOFFSETTO:" + formatUTCOffset(to))
+	iw.line("TZNAME:" + name)
+	iw.line("END:" + kind)
+}
+
+func formatUTCOffset(off int) string {
+	sign := '+'
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, off/3600, off%3600/60)
+}
+
+func parseUTCOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 {
+		return 0, fmt.Errorf("bad utc offset %q", s)
+	}
+	var sign int
+	switch s[0] {
+	case '+':
+		sign = 1
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("bad utc offset %q", s)
+	}
+	h, err := strconv.Atoi(s[1:3])
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(s[3:5])
+	if err != nil {
+		return 0, err
+	}
+	secs := 0
+	if len(s) == 7 {
+		if secs, err = strconv.Atoi(s[5:]); err != nil {
+			return 0, err
+		}
+	}
+	return sign * (h*3600 + m*60 + secs), nil
+}
+
+var icsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+var icsUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func escapeICS(s string) string { return icsEscaper.Replace(s) }
+
+func unescapeICS(s string) string { return icsUnescaper.Replace(s) }
+
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if len(l) > 0 && (l[0] == ' ' || l[0] == '\t') {
+			if len(lines) == 0 {
+				return nil, errors.New("ics: continuation line without a property")
+			}
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// splitICSLine splits a content line into its name, parameters and value.
+func splitICSLine(l string) (name string, params map[string]string, value string) {
+	var (
+		quoted bool
+		i      int
+	)
+	for i = 0; i < len(l); i++ {
+		if l[i] == '"' {
+			quoted = !quoted
+		} else if l[i] == ':' && !quoted {
+			break
+		}
+	}
+	head := l[:i]
+	if i < len(l) {
+		value = l[i+1:]
+	}
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return name, params, value
+}
+
+func setICSProperty(
+	e *CalendarEvent,
+	name string,
+	params map[string]string,
+	value string,
+	zones map[string]*time.Location,
+) (err error) {
+	switch name {
+	case "SUMMARY":
+		e.Title = unescapeICS(value)
+	case "DESCRIPTION":
+		e.Description = unescapeICS(value)
+	case "LOCATION":
+		e.LocationName = unescapeICS(value)
+	case "DTSTART":
+		var allDay bool
+		e.StartAt, allDay, err = parseICSTime(value, params, zones)
+		if allDay {
+			e.AllDay = true
+			e.AllDayDate = e.StartAt.Format("2006-01-02")
+		}
+	case "DTEND":
+		e.EndAt, _, err = parseICSTime(value, params, zones)
+	case "RRULE":
+		e.Duplicate = parseRRule(value)
+	}
+	return err
+}
+
+func parseICSTime(
+	value string,
+	params map[string]string,
+	zones map[string]*time.Location,
+) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len(icsDateFormat) {
+		t, err := time.Parse(icsDateFormat, value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsDateTimeFormat, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid, ok := params["TZID"]; ok {
+		if l, ok := zones[tzid]; ok {
+			loc = l
+		} else if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(icsDateTimeFormat, value, loc)
+	return t, false, err
+}
+
+// parseICSDuration parses durations like "PT1H30M" or "P1W".
+func parseICSDuration(s string) (time.Duration, error) {
+	var (
+		d    time.Duration
+		sign time.Duration = 1
+		num  int
+		orig = s
+	)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("bad duration %q", orig)
+	}
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num = num*10 + int(c-'0')
+			continue
+		case c == 'T':
+			continue
+		case c == 'W':
+			d += time.Duration(num) * 7 * 24 * time.Hour
+		case c == 'D':
+			d += time.Duration(num) * 24 * time.Hour
+		case c == 'H':
+			d += time.Duration(num) * time.Hour
+		case c == 'M':
+			d += time.Duration(num) * time.Minute
+		case c == 'S':
+			d += time.Duration(num) * time.Second
+		default:
+			return 0, fmt.Errorf("bad duration %q", orig)
+		}
+		num = 0
+	}
+	return sign * d, nil
+}
+
+// parseRRule converts a recurrence rule to an EventDuplicate. Only
+// rules that canvas can represent are converted, all others return nil.
+func parseRRule(rule string) *EventDuplicate {
+	dup := &EventDuplicate{}
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.ToUpper(kv[0]) {
+		case "FREQ":
+			switch strings.ToUpper(kv[1]) {
+			case "DAILY":
+				dup.Frequency = RepeatDaily
+			case "WEEKLY":
+				dup.Frequency = RepeatWeekly
+			case "MONTHLY":
+				dup.Frequency = RepeatMonthly
+			}
+		case "COUNT":
+			// canvas counts the copies, not including the first event
+			n, _ := strconv.Atoi(kv[1])
+			dup.Count = n - 1
+		case "INTERVAL":
+			dup.Interval, _ = strconv.Atoi(kv[1])
+		}
+	}
+	if dup.Frequency == "" || dup.Count < 1 {
+		return nil
+	}
+	return dup
+}
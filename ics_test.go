@@ -0,0 +1,90 @@
+package canvas
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestWriteICS(t *testing.T) {
+	is := is.New(t)
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	events := []*CalendarEvent{
+		{
+			ID:           1,
+			Title:        "Lecture; part 1, intro",
+			Description:  "line one\nline two " + strings.Repeat("long ", 20),
+			StartAt:      time.Date(2020, 9, 1, 9, 0, 0, 0, denver),
+			EndAt:        time.Date(2020, 9, 1, 10, 30, 0, 0, denver),
+			LocationName: "Room 101",
+		},
+		{ID: 2, Title: "Holiday", AllDay: true, AllDayDate: "2020-11-26"},
+	}
+	var buf bytes.Buffer
+	is.NoErr((&Account{DefaultTimeZone: "America/Denver"}).WriteICS(&buf, events))
+	out := buf.String()
+	for _, l := range strings.Split(out, "\r\n") {
+		is.True(len(l) <= 75)
+	}
+	is.True(strings.Contains(out, "BEGIN:VTIMEZONE\r\nTZID:America/Denver\r\n"))
+	is.True(strings.Contains(out, "BEGIN:DAYLIGHT\r\nDTSTART:20200308T020000\r\nTZOFFSETFROM:-0700\r\nTZOFFSETTO:-0600\r\n"))
+	is.True(strings.Contains(out, "DTSTART;TZID=America/Denver:20200901T090000\r\n"))
+	is.True(strings.Contains(out, `SUMMARY:Lecture\; part 1\, intro`))
+	is.True(strings.Contains(out, "DTSTART;VALUE=DATE:20201126\r\nDTEND;VALUE=DATE:20201127\r\n"))
+
+	parsed, err := ParseICS(&buf)
+	is.NoErr(err)
+	is.Equal(len(parsed), 2)
+	is.Equal(parsed[0].Title, events[0].Title)
+	is.Equal(parsed[0].Description, events[0].Description)
+	is.Equal(parsed[0].LocationName, "Room 101")
+	is.True(parsed[0].StartAt.Equal(events[0].StartAt))
+	is.True(parsed[0].EndAt.Equal(events[0].EndAt))
+	is.True(parsed[1].AllDay)
+	is.Equal(parsed[1].AllDayDate, "2020-11-26")
+}
+
+func TestParseICS(t *testing.T) {
+	is := is.New(t)
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTIMEZONE\r\n" +
+		"TZID:Custom Time\r\n" +
+		"BEGIN:STANDARD\r\n" +
+		"DTSTART:19700101T000000\r\n" +
+		"TZOFFSETFROM:+0530\r\n" +
+		"TZOFFSETTO:+0530\r\n" +
+		"END:STANDARD\r\n" +
+		"END:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Weekly\r\n" +
+		"  lab\r\n" +
+		"DTSTART;TZID=\"Custom Time\":20200901T140000\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=10;INTERVAL=2\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Yearly\r\n" +
+		"DURATION:PT30M\r\n" + // before DTSTART is valid
+		"DTSTART:20200901T140000Z\r\n" +
+		"RRULE:FREQ=YEARLY;COUNT=3\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ParseICS(strings.NewReader(ics))
+	is.NoErr(err)
+	is.Equal(len(events), 2)
+	e := events[0]
+	is.Equal(e.Title, "Weekly lab")
+	_, off := e.StartAt.Zone()
+	is.Equal(off, 5*3600+30*60)
+	is.Equal(e.EndAt.Sub(e.StartAt), 90*time.Minute)
+	is.Equal(*e.Duplicate, EventDuplicate{Count: 9, Interval: 2, Frequency: RepeatWeekly})
+	is.True(events[1].Duplicate == nil)
+	is.True(events[1].StartAt.Equal(time.Date(2020, 9, 1, 14, 0, 0, 0, time.UTC)))
+	is.True(events[1].EndAt.Equal(time.Date(2020, 9, 1, 14, 30, 0, 0, time.UTC)))
+}
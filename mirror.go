@@ -0,0 +1,319 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultManifestName is the name of the manifest file written to the
+// root of a mirrored directory when no other name is given.
+const DefaultManifestName = ".canvas-manifest.json"
+
+// MirrorOptions changes the behavior of a mirror operation.
+type MirrorOptions struct {
+	// Concurrency is the max number of files that are
+	// downloaded at the same time. Defaults to 4.
+	Concurrency int
+	// Delete will remove local files that are no longer in canvas along
+	// with any directories left empty. When false, stale files are only
+	// reported.
+	Delete bool
+	// Manifest is the path of the manifest file used to make later
+	// runs incremental. Relative paths are relative to the mirrored
+	// directory. Defaults to DefaultManifestName.
+	Manifest string
+}
+
+// MirrorResult is a summary of a mirror operation. All paths
+// are relative to the mirrored directory.
+type MirrorResult struct {
+	// Downloaded are files that were new or changed.
+	Downloaded []string
+	// Unchanged are files that were already up to date.
+	Unchanged []string
+	// Stale are local files that are no longer in canvas.
+	Stale []string
+	// Removed are the stale files that were deleted.
+	Removed []string
+	// Errors holds the error for each file that failed.
+	Errors map[string]error
+}
+
+// Manifest records the state of each file in a mirrored directory.
+type Manifest struct {
+	UpdatedAt time.Time                `json:"updated_at"`
+	Files     map[string]ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is the state of a single file when it was last downloaded.
+type ManifestEntry struct {
+	ID        int       `json:"id"`
+	Size      int       `json:"size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Mirror will recreate the course's folder tree in a local directory and
+// download every file that is new or has changed since the last run. A
+// manifest is kept in the directory so that unchanged files are skipped.
+// Downloads that fail part way are resumed on the next run.
+func (c *Course) Mirror(dir string, opts *MirrorOptions) (*MirrorResult, error) {
+	folders, err := c.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := c.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	return mirror(dir, folders, files, opts)
+}
+
+// Mirror will recreate the group's folder tree in a local directory and
+// download every file that is new or has changed since the last run.
+func (g *Group) Mirror(dir string, opts *MirrorOptions) (*MirrorResult, error) {
+	folders, err := g.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := g.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	return mirror(dir, folders, files, opts)
+}
+
+// ReadManifest will read a manifest file.
+func ReadManifest(filename string) (*Manifest, error) {
+	m := &Manifest{Files: make(map[string]ManifestEntry)}
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return m, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
+
+// WriteFile will write the manifest to a file.
+func (m *Manifest) WriteFile(filename string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func (m *Manifest) changed(rel string, f *File, local string) bool {
+	entry, ok := m.Files[rel]
+	if !ok || entry.ID != f.ID || entry.Size != f.Size || !entry.UpdatedAt.Equal(f.UpdatedAt) {
+		return true
+	}
+	info, err := os.Stat(local)
+	return err != nil || info.Size() != int64(f.Size)
+}
+
+func mirror(dir string, folders []*Folder, files []*File, opts *MirrorOptions) (*MirrorResult, error) {
+	if opts == nil {
+		opts = &MirrorOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	manifestPath := opts.Manifest
+	if manifestPath == "" {
+		manifestPath = DefaultManifestName
+	}
+	if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(dir, manifestPath)
+	}
+	old, err := ReadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := folderPaths(folders)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		local, err := localPath(dir, p)
+		if err != nil {
+			return nil, err
+		}
+		if err = os.MkdirAll(local, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, concurrency)
+		res    = &MirrorResult{Errors: make(map[string]error)}
+		next   = &Manifest{Files: make(map[string]ManifestEntry, len(files))}
+		remote = make(map[string]bool, len(files))
+	)
+	// the results are shared with the download goroutines
+	// so they are only ever changed while holding mu
+	fail := func(rel string, err error) {
+		mu.Lock()
+		res.Errors[rel] = err
+		mu.Unlock()
+	}
+	for _, f := range files {
+		folder, ok := paths[f.FolderID]
+		if !ok {
+			fail(f.DisplayName, fmt.Errorf("could not find folder %d", f.FolderID))
+			continue
+		}
+		rel := filepath.ToSlash(filepath.Join(folder, f.DisplayName))
+		remote[rel] = true
+		local, err := localPath(dir, rel)
+		if err != nil {
+			fail(rel, err)
+			continue
+		}
+		entry := ManifestEntry{ID: f.ID, Size: f.Size, UpdatedAt: f.UpdatedAt}
+		if !old.changed(rel, f, local) {
+			mu.Lock()
+			res.Unchanged = append(res.Unchanged, rel)
+			next.Files[rel] = entry
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(f *File, rel, local string) {
+			defer func() { <-sem; wg.Done() }()
+			err := f.download(local, nil)
+			if err == nil {
+				// the manifest and resumed downloads compare against UpdatedAt
+				err = os.Chtimes(local, f.UpdatedAt, f.UpdatedAt)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				res.Errors[rel] = err
+				return
+			}
+			res.Downloaded = append(res.Downloaded, rel)
+			next.Files[rel] = entry
+		}(f, rel, local)
+	}
+	wg.Wait()
+
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || p == manifestPath || p == manifestPath+".tmp" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if remote[rel] {
+			return nil
+		}
+		res.Stale = append(res.Stale, rel)
+		if opts.Delete {
+			if err = os.Remove(p); err != nil {
+				res.Errors[rel] = err
+			} else {
+				res.Removed = append(res.Removed, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	if opts.Delete {
+		removeEmptyDirs(dir, res.Removed, paths)
+	}
+
+	sort.Strings(res.Downloaded)
+	sort.Strings(res.Unchanged)
+	next.UpdatedAt = time.Now()
+	return res, next.WriteFile(manifestPath)
+}
+
+// folderPaths maps each folder id to its path relative to the root folder.
+func folderPaths(folders []*Folder) (map[int]string, error) {
+	var root *Folder
+	for _, f := range folders {
+		if f.ParentFolderID == 0 {
+			root = f
+			break
+		}
+	}
+	if root == nil {
+		return nil, errors.New("could not find root folder")
+	}
+	paths := make(map[int]string, len(folders))
+	for _, f := range folders {
+		rel := strings.TrimPrefix(f.FullName, root.FullName)
+		paths[f.ID] = strings.TrimPrefix(rel, "/")
+	}
+	return paths, nil
+}
+
+// removeEmptyDirs removes the directories left empty after deleting
+// stale files. Directories that are still folders in canvas are kept.
+func removeEmptyDirs(dir string, removed []string, folders map[int]string) {
+	keep := make(map[string]bool, len(folders))
+	for _, p := range folders {
+		keep[p] = true
+	}
+	dirs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, rel := range removed {
+		for d := path.Dir(rel); d != "." && !keep[d] && !seen[d]; d = path.Dir(d) {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	// remove the deepest directories first so that
+	// parents are empty by the time they are reached
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/")
+	})
+	for _, d := range dirs {
+		// fails for directories that are not empty which is what we want
+		os.Remove(filepath.Join(dir, filepath.FromSlash(d)))
+	}
+}
+
+// localPath joins a relative canvas path to the local directory making sure
+// that the result does not end up outside of the directory.
+func localPath(dir, rel string) (string, error) {
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	r, err := filepath.Rel(dir, p)
+	if err != nil {
+		return "", err
+	}
+	if r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside of %q", rel, dir)
+	}
+	return p, nil
+}
@@ -0,0 +1,168 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestCourseMirror(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "canvas-mirror")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	var downloads int32
+	updated := "2020-09-01T00:00:00Z"
+	mux.HandleFunc("/api/v1/courses/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[
+			{"id":1,"full_name":"course files","name":"course files"},
+			{"id":2,"parent_folder_id":1,"full_name":"course files/notes","name":"notes"},
+			{"id":3,"parent_folder_id":2,"full_name":"course files/notes/empty","name":"empty"}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		fmt.Fprintf(w, `[
+			{"id":10,"folder_id":1,"display_name":"syllabus.txt","size":8,"updated_at":%[1]q,"url":"%[2]s/download/10"},
+			{"id":11,"folder_id":2,"display_name":"week1.txt","size":5,"updated_at":%[1]q,"url":"%[2]s/download/11"}]`,
+			updated, server.URL)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		switch r.URL.Path {
+		case "/download/10":
+			w.Write([]byte("syllabus"))
+		case "/download/11":
+			w.Write([]byte("week1"))
+		}
+	})
+
+	course := &Course{ID: 1, client: client}
+	res, err := course.Mirror(dir, &MirrorOptions{Concurrency: 2})
+	is.NoErr(err)
+	is.Equal(len(res.Errors), 0)
+	is.Equal(res.Downloaded, []string{"notes/week1.txt", "syllabus.txt"})
+	is.Equal(downloads, int32(2))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "notes", "week1.txt"))
+	is.NoErr(err)
+	is.Equal(string(b), "week1")
+	info, err := os.Stat(filepath.Join(dir, "notes", "empty"))
+	is.NoErr(err)
+	is.True(info.IsDir())
+	_, err = os.Stat(filepath.Join(dir, DefaultManifestName))
+	is.NoErr(err)
+
+	// nothing has changed so nothing should be downloaded
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "old.txt"), []byte("old"), 0644))
+	res, err = course.Mirror(dir, nil)
+	is.NoErr(err)
+	is.Equal(len(res.Downloaded), 0)
+	is.Equal(len(res.Unchanged), 2)
+	is.Equal(res.Stale, []string{"old.txt"})
+	is.Equal(len(res.Removed), 0)
+	is.Equal(downloads, int32(2))
+
+	updated = "2020-09-02T00:00:00Z"
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "gone", "deep"), 0755))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "gone", "deep", "old.txt"), []byte("old"), 0644))
+	res, err = course.Mirror(dir, &MirrorOptions{Delete: true})
+	is.NoErr(err)
+	is.Equal(len(res.Downloaded), 2)
+	is.Equal(res.Removed, []string{"gone/deep/old.txt", "old.txt"})
+	_, err = os.Stat(filepath.Join(dir, "old.txt"))
+	is.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "gone"))
+	is.True(os.IsNotExist(err)) // empty directories are removed
+	_, err = os.Stat(filepath.Join(dir, "notes", "empty"))
+	is.NoErr(err) // empty folders that are in canvas are kept
+}
+
+// run with -race, errors are recorded from the main loop
+// and the download goroutines at the same time
+func TestCourseMirror_Errors(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "canvas-mirror")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	mux.HandleFunc("/api/v1/courses/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":1,"full_name":"course files","name":"course files"}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte("["))
+		for i := 0; i < 40; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			// odd files are in a folder that does not exist
+			fmt.Fprintf(w, `{"id":%d,"folder_id":%d,"display_name":"%d.txt","size":1,"url":"%s/missing/%[1]d"}`,
+				i, 1+i%2, i, server.URL)
+		}
+		w.Write([]byte("]"))
+	})
+	mux.HandleFunc("/missing/", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	course := &Course{ID: 1, client: client}
+	res, err := course.Mirror(dir, &MirrorOptions{Concurrency: 8})
+	is.NoErr(err)
+	is.Equal(len(res.Errors), 40)
+	is.Equal(len(res.Downloaded), 0)
+}
+
+func TestCourseMirror_Resume(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "canvas-mirror")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	mux.HandleFunc("/api/v1/courses/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":1,"full_name":"course files","name":"course files"}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		fmt.Fprintf(w, `[
+			{"id":10,"folder_id":1,"display_name":"syllabus.txt","size":8,"updated_at":"2020-09-01T00:00:00Z","url":"%[1]s/download/10"},
+			{"id":11,"folder_id":1,"display_name":"short.txt","size":8,"updated_at":"2020-09-01T00:00:00Z","url":"%[1]s/download/11"}]`,
+			server.URL)
+	})
+	var ranges []string
+	mux.HandleFunc("/download/10", func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "syllabus.txt", time.Time{}, strings.NewReader("syllabus"))
+	})
+	mux.HandleFunc("/download/11", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("short")) // cut off
+	})
+
+	// left behind by a download that failed part way
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "syllabus.txt"), []byte("syll"), 0644))
+	res, err := (&Course{ID: 1, client: client}).Mirror(dir, nil)
+	is.NoErr(err)
+	is.Equal(res.Downloaded, []string{"syllabus.txt"})
+	is.Equal(ranges, []string{"bytes=4-"})
+	b, err := ioutil.ReadFile(filepath.Join(dir, "syllabus.txt"))
+	is.NoErr(err)
+	is.Equal(string(b), "syllabus")
+	is.True(errors.Is(res.Errors["short.txt"], ErrIncompleteDownload))
+}
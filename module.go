@@ -0,0 +1,433 @@
+package canvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// Module is a course module.
+//
+// https://canvas.instructure.com/doc/api/modules.html
+type Module struct {
+	ID                        int       `json:"id" url:"-"`
+	Name                      string    `json:"name" url:"name,omitempty"`
+	Position                  int       `json:"position" url:"position,omitempty"`
+	UnlockAt                  time.Time `json:"unlock_at" url:"unlock_at,omitempty"`
+	RequireSequentialProgress bool      `json:"require_sequential_progress" url:"require_sequential_progress,omitempty"`
+	PrerequisiteModuleIDs     []int     `json:"prerequisite_module_ids" url:"prerequisite_module_ids,brackets,omitempty"`
+	PublishFinalGrade         bool      `json:"publish_final_grade" url:"publish_final_grade,omitempty"`
+	Published                 bool      `json:"published" url:"published,omitempty"`
+
+	WorkflowState string        `json:"workflow_state" url:"-"`
+	ItemsCount    int           `json:"items_count" url:"-"`
+	ItemsURL      string        `json:"items_url" url:"-"`
+	Items         []*ModuleItem `json:"items" url:"-"`
+
+	// State is the state of the module for the student given
+	// when the module was requested, it will be one of "locked",
+	// "unlocked", "started", or "completed"
+	State       string    `json:"state" url:"-"`
+	CompletedAt time.Time `json:"completed_at" url:"-"`
+
+	courseID int
+	client   doer
+}
+
+type moduleOptions struct {
+	Module `url:"module"`
+}
+
+// Modules will get the course's modules. Use IncludeOpt("items") to
+// include the module items.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.index
+func (c *Course) Modules(opts ...Option) (mods []*Module, err error) {
+	ch := make(chan *Module)
+	pager := newPaginatedList(
+		c.client, c.id("/courses/%d/modules"),
+		sendModulesFunc(c.client, c.ID, ch), opts,
+	)
+	errs := pager.start()
+	for {
+		select {
+		case m := <-ch:
+			mods = append(mods, m)
+		case err := <-errs:
+			return mods, err
+		}
+	}
+}
+
+// ModuleProgress will get the course's modules along with the
+// module items and the progress a student has made in each one.
+func (c *Course) ModuleProgress(studentID int, opts ...Option) ([]*Module, error) {
+	opts = append(opts, Opt("student_id", studentID))
+	return c.Modules(withInclude(opts, "items")...)
+}
+
+// Module will get a module given its id.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.show
+func (c *Course) Module(id int, opts ...Option) (*Module, error) {
+	m := &Module{client: c.client, courseID: c.ID}
+	if err := getjson(c.client, m, optEnc(opts), "/courses/%d/modules/%d", c.ID, id); err != nil {
+		return nil, err
+	}
+	m.setItemsClient()
+	return m, nil
+}
+
+// CreateModule will create a new module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.create
+func (c *Course) CreateModule(m Module) (*Module, error) {
+	q, err := query.Values(&moduleOptions{m})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(c.client, c.id("/courses/%d/modules"), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	mod := &Module{client: c.client, courseID: c.ID}
+	return mod, json.NewDecoder(resp.Body).Decode(mod)
+}
+
+// EditModule will edit the module given and return the new edited module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.update
+func (c *Course) EditModule(m *Module) (*Module, error) {
+	q, err := query.Values(&moduleOptions{*m})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := put(c.client, fmt.Sprintf("/courses/%d/modules/%d", c.ID, m.ID), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	mod := &Module{client: c.client, courseID: c.ID}
+	return mod, json.NewDecoder(resp.Body).Decode(mod)
+}
+
+// DeleteModule will delete a module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.destroy
+func (c *Course) DeleteModule(m *Module) error {
+	resp, err := delete(c.client, fmt.Sprintf("/courses/%d/modules/%d", c.ID, m.ID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ReorderModules will set the position of each module to the order
+// they are given in.
+func (c *Course) ReorderModules(modules ...*Module) error {
+	for i, m := range modules {
+		if m.client == nil {
+			m.client, m.courseID = c.client, c.ID
+		}
+		if err := m.SetPosition(i + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish will publish the module.
+func (m *Module) Publish() error {
+	return m.update(params{"module[published]": {"true"}})
+}
+
+// Unpublish will unpublish the module.
+func (m *Module) Unpublish() error {
+	return m.update(params{"module[published]": {"false"}})
+}
+
+// SetPosition will move the module to a new position in the course.
+func (m *Module) SetPosition(position int) error {
+	return m.update(params{"module[position]": {strconv.Itoa(position)}})
+}
+
+// SetPrerequisites will set the modules that must be completed
+// before this module is unlocked. Calling SetPrerequisites with no
+// modules will remove all prerequisites.
+func (m *Module) SetPrerequisites(prereqs ...*Module) error {
+	ids := make([]string, len(prereqs))
+	for i, p := range prereqs {
+		ids[i] = strconv.Itoa(p.ID)
+	}
+	if len(ids) == 0 {
+		// canvas will only clear the prerequisites when sent an empty value
+		ids = []string{""}
+	}
+	return m.update(params{"module[prerequisite_module_ids][]": ids})
+}
+
+// Relock will reset the module progress of all students so that
+// requirements and prerequisites are evaluated again.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_modules_api.relock
+func (m *Module) Relock() error {
+	resp, err := put(m.client, m.path("/relock"), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(m)
+}
+
+// ListItems will get the module's items.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.index
+func (m *Module) ListItems(opts ...Option) (items []*ModuleItem, err error) {
+	ch := make(chan *ModuleItem)
+	pager := newPaginatedList(m.client, m.path("/items"), func(r io.Reader) error {
+		list := make([]*ModuleItem, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, item := range list {
+			item.client, item.courseID = m.client, m.courseID
+			ch <- item
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case item := <-ch:
+			items = append(items, item)
+		case err := <-errs:
+			return items, err
+		}
+	}
+}
+
+// Item will get a module item given its id.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.show
+func (m *Module) Item(id int, opts ...Option) (*ModuleItem, error) {
+	item := &ModuleItem{client: m.client, courseID: m.courseID}
+	return item, getjson(m.client, item, optEnc(opts), m.path("/items/%d"), id)
+}
+
+// CreateItem will add a new item to the module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.create
+func (m *Module) CreateItem(item ModuleItem) (*ModuleItem, error) {
+	q, err := query.Values(&moduleItemOptions{item})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(m.client, m.path("/items"), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	newitem := &ModuleItem{client: m.client, courseID: m.courseID}
+	return newitem, json.NewDecoder(resp.Body).Decode(newitem)
+}
+
+// EditItem will edit a module item and return the new edited item.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.update
+func (m *Module) EditItem(item *ModuleItem) (*ModuleItem, error) {
+	q, err := query.Values(&moduleItemOptions{*item})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := put(m.client, fmt.Sprintf(m.path("/items/%d"), item.ID), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	newitem := &ModuleItem{client: m.client, courseID: m.courseID}
+	return newitem, json.NewDecoder(resp.Body).Decode(newitem)
+}
+
+// DeleteItem will remove an item from the module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.destroy
+func (m *Module) DeleteItem(item *ModuleItem) error {
+	resp, err := delete(m.client, fmt.Sprintf(m.path("/items/%d"), item.ID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (m *Module) update(p params) error {
+	resp, err := put(m.client, m.path(""), p)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(m)
+}
+
+func (m *Module) path(suffix string) string {
+	return fmt.Sprintf("/courses/%d/modules/%d", m.courseID, m.ID) + suffix
+}
+
+func (m *Module) setItemsClient() {
+	for _, item := range m.Items {
+		item.client, item.courseID = m.client, m.courseID
+	}
+}
+
+// Module item types
+const (
+	ModuleItemFile         = "File"
+	ModuleItemPage         = "Page"
+	ModuleItemDiscussion   = "Discussion"
+	ModuleItemAssignment   = "Assignment"
+	ModuleItemQuiz         = "Quiz"
+	ModuleItemSubHeader    = "SubHeader"
+	ModuleItemExternalURL  = "ExternalUrl"
+	ModuleItemExternalTool = "ExternalTool"
+)
+
+// ModuleItem is an item in a module.
+//
+// https://canvas.instructure.com/doc/api/modules.html#ModuleItem
+type ModuleItem struct {
+	ID       int    `json:"id" url:"-"`
+	ModuleID int    `json:"module_id" url:"module_id,omitempty"`
+	Title    string `json:"title" url:"title,omitempty"`
+	Position int    `json:"position" url:"position,omitempty"`
+	Indent   int    `json:"indent" url:"indent,omitempty"`
+	// Type can be any of the ModuleItem* constants
+	Type        string `json:"type" url:"type,omitempty"`
+	ContentID   int    `json:"content_id" url:"content_id,omitempty"`
+	PageURL     string `json:"page_url" url:"page_url,omitempty"`
+	ExternalURL string `json:"external_url" url:"external_url,omitempty"`
+	NewTab      bool   `json:"new_tab" url:"new_tab,omitempty"`
+	Published   bool   `json:"published" url:"published,omitempty"`
+
+	CompletionRequirement *CompletionRequirement `json:"completion_requirement" url:"completion_requirement,omitempty"`
+
+	HTMLURL        string `json:"html_url" url:"-"`
+	URL            string `json:"url" url:"-"`
+	ContentDetails struct {
+		PointsPossible  float64   `json:"points_possible"`
+		DueAt           time.Time `json:"due_at"`
+		UnlockAt        time.Time `json:"unlock_at"`
+		LockAt          time.Time `json:"lock_at"`
+		LockedForUser   bool      `json:"locked_for_user"`
+		LockExplanation string    `json:"lock_explanation"`
+	} `json:"content_details" url:"-"`
+
+	courseID int
+	client   doer
+}
+
+type moduleItemOptions struct {
+	ModuleItem `url:"module_item"`
+}
+
+// CompletionRequirement is the requirement that must be met for
+// a module item to be considered complete.
+type CompletionRequirement struct {
+	// Type can be any of "must_view", "must_contribute", "must_submit",
+	// "must_mark_done", or "min_score"
+	Type     string  `json:"type" url:"type"`
+	MinScore float64 `json:"min_score" url:"min_score,omitempty"`
+	// Completed is only sent when requesting the item for a student.
+	Completed bool `json:"completed" url:"-"`
+}
+
+// Content will get the object that the module item refers to. The value
+// returned will be an *Assignment, *File, *Page, *Quiz, or *DiscussionTopic
+// depending on the item's type. Items that have no content, like
+// sub-headers or external urls, will return an error.
+func (mi *ModuleItem) Content() (interface{}, error) {
+	switch mi.Type {
+	case ModuleItemAssignment:
+		c := &Course{ID: mi.courseID, client: mi.client}
+		return c.Assignment(mi.ContentID)
+	case ModuleItemFile:
+		f := &File{client: mi.client}
+		return f, getjson(mi.client, f, nil, "/courses/%d/files/%d", mi.courseID, mi.ContentID)
+	case ModuleItemPage:
+		return getPage(mi.client, fmt.Sprintf("courses/%d", mi.courseID), mi.PageURL, nil)
+	case ModuleItemQuiz:
+		return getQuiz(mi.client, mi.courseID, mi.ContentID, nil)
+	case ModuleItemDiscussion:
+		return getDiscussionTopic(mi.client, fmt.Sprintf("courses/%d", mi.courseID), mi.ContentID, nil)
+	}
+	return nil, fmt.Errorf("module item of type %q has no content", mi.Type)
+}
+
+// MarkDone will mark the module item as done, this is only for
+// items with a "must_mark_done" completion requirement.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.mark_as_done
+func (mi *ModuleItem) MarkDone() error {
+	return mi.markDone("PUT")
+}
+
+// MarkNotDone will mark the module item as not done.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.mark_as_not_done
+func (mi *ModuleItem) MarkNotDone() error {
+	return mi.markDone("DELETE")
+}
+
+// MarkRead will mark the module item as read, fulfilling any
+// "must_view" requirements.
+//
+// https://canvas.instructure.com/doc/api/modules.html#method.context_module_items_api.mark_item_read
+func (mi *ModuleItem) MarkRead() error {
+	resp, err := post(mi.client, mi.path("/mark_read"), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SetPosition will move the item to a new position in its module.
+func (mi *ModuleItem) SetPosition(position int) error {
+	resp, err := put(mi.client, mi.path(""), params{
+		"module_item[position]": {strconv.Itoa(position)},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(mi)
+}
+
+func (mi *ModuleItem) markDone(method string) error {
+	resp, err := do(mi.client, newreq(method, mi.path("/done"), nil))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (mi *ModuleItem) path(suffix string) string {
+	return fmt.Sprintf("/courses/%d/modules/%d/items/%d", mi.courseID, mi.ModuleID, mi.ID) + suffix
+}
+
+func sendModulesFunc(d doer, courseID int, ch chan *Module) sendFunc {
+	return func(r io.Reader) error {
+		list := make([]*Module, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, m := range list {
+			m.client, m.courseID = d, courseID
+			m.setItemsClient()
+			ch <- m
+		}
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestModules(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/courses/1/modules", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.Method {
+		case "POST":
+			is.Equal(q.Get("module[name]"), "Week 1")
+			is.Equal(q["module[prerequisite_module_ids][]"], []string{"4", "5"})
+			is.Equal(q.Get("module[published]"), "")
+			w.Write([]byte(`{"id":6,"name":"Week 1","prerequisite_module_ids":[4,5]}`))
+		case "GET":
+			is.Equal(q.Get("student_id"), "2")
+			is.Equal(q["include[]"], []string{"items", "content_details"})
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(`[{"id":6,"name":"Week 1","state":"started","items":[
+				{"id":7,"module_id":6,"type":"Page","page_url":"intro","completion_requirement":{"type":"must_view","completed":true}},
+				{"id":8,"module_id":6,"type":"Quiz","content_id":9,"completion_requirement":{"type":"must_mark_done"}},
+				{"id":10,"module_id":6,"type":"SubHeader"},
+				{"id":12,"module_id":6,"type":"Discussion","content_id":13}]}]`))
+		}
+	})
+	mux.HandleFunc("/api/v1/courses/1/modules/6", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		is.Equal(r.URL.Query().Get("module[published]"), "true")
+		w.Write([]byte(`{"id":6,"name":"Week 1","published":true}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/pages/intro", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page_id":11,"url":"intro","title":"Introduction"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/quizzes/9", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":9,"title":"Quiz 1"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/13", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":13,"title":"Week 1 discussion"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/discussion_topics/13/subscribed", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v1/courses/1/modules/6/items/8/done", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+	})
+
+	course := &Course{ID: 1, client: client}
+	mod, err := course.CreateModule(Module{Name: "Week 1", PrerequisiteModuleIDs: []int{4, 5}})
+	is.NoErr(err)
+	is.Equal(mod.ID, 6)
+	is.NoErr(mod.Publish())
+	is.True(mod.Published)
+
+	mods, err := course.ModuleProgress(2, IncludeOpt("content_details"))
+	is.NoErr(err)
+	is.Equal(len(mods), 1)
+	is.Equal(mods[0].State, "started")
+	items := mods[0].Items
+	is.Equal(len(items), 4)
+	is.True(items[0].CompletionRequirement.Completed)
+
+	content, err := items[0].Content()
+	is.NoErr(err)
+	page, ok := content.(*Page)
+	is.True(ok)
+	is.Equal(page.Title, "Introduction")
+	content, err = items[1].Content()
+	is.NoErr(err)
+	quiz, ok := content.(*Quiz)
+	is.True(ok)
+	is.Equal(quiz.ID, 9)
+	_, err = items[2].Content()
+	is.True(err != nil)
+	content, err = items[3].Content()
+	is.NoErr(err)
+	topic, ok := content.(*DiscussionTopic)
+	is.True(ok)
+	is.NoErr(topic.Subscribe()) // topic can make requests
+	is.NoErr(items[1].MarkDone())
+}
@@ -24,6 +24,15 @@ var (
 	OptDesigner Option = Opt("enrollment_type", "designer")
 )
 
+// Conversation scopes are given when listing conversations to filter
+// the conversations by state.
+var (
+	UnreadConversations   Option = Opt("scope", "unread")
+	StarredConversations  Option = Opt("scope", "starred")
+	ArchivedConversations Option = Opt("scope", "archived")
+	SentConversations     Option = Opt("scope", "sent")
+)
+
 // Option is a key value pair used
 // for api parameters. see Opt
 type Option interface {
@@ -65,6 +74,22 @@ func IncludeOpt(vals ...string) Option {
 	}
 }
 
+// withInclude adds vals to the include[] option. Any includes already in
+// opts are merged into the same option because params.Add would replace
+// one with the other.
+func withInclude(opts []Option, vals ...string) optEnc {
+	include := append([]string{}, vals...)
+	res := make(optEnc, 0, len(opts)+1)
+	for _, o := range opts {
+		if o.Name() == "include[]" {
+			include = append(include, o.Value()...)
+			continue
+		}
+		res = append(res, o)
+	}
+	return append(res, IncludeOpt(include...))
+}
+
 // SortOpt returns a sorting option
 func SortOpt(schemes ...string) Option {
 	return ArrayOpt("sort", schemes...)
@@ -0,0 +1,365 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// Page is a wiki page.
+//
+// https://canvas.instructure.com/doc/api/pages.html
+type Page struct {
+	ID    int    `json:"page_id" url:"-"`
+	URL   string `json:"url" url:"-"`
+	Title string `json:"title" url:"title,omitempty"`
+	Body  string `json:"body" url:"body,omitempty"`
+	// EditingRoles is a comma separated list of the roles allowed to edit
+	// the page. Any combination of "teachers", "students", "members", or
+	// "public".
+	EditingRoles string `json:"editing_roles" url:"editing_roles,omitempty"`
+	Published    bool   `json:"published" url:"published,omitempty"`
+	FrontPage    bool   `json:"front_page" url:"front_page,omitempty"`
+
+	CreatedAt        time.Time `json:"created_at" url:"-"`
+	UpdatedAt        time.Time `json:"updated_at" url:"-"`
+	HideFromStudents bool      `json:"hide_from_students" url:"-"`
+	LastEditedBy     *User     `json:"last_edited_by" url:"-"`
+	TodoDate         time.Time `json:"todo_date" url:"-"`
+	RevisionID       int       `json:"revision_id" url:"-"`
+	HTMLURL          string    `json:"html_url" url:"-"`
+	LockedForUser    bool      `json:"locked_for_user" url:"-"`
+	LockInfo         *LockInfo `json:"lock_info" url:"-"`
+	LockExplanation  string    `json:"lock_explanation" url:"-"`
+
+	client doer
+	path   string // context path, ex. "courses/1234"
+}
+
+type pageOptions struct {
+	Page `url:"wiki_page"`
+}
+
+// Pages will get the course's wiki pages.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.index
+func (c *Course) Pages(opts ...Option) ([]*Page, error) {
+	return listPages(c.client, c.id("courses/%d"), opts)
+}
+
+// Page will get a course wiki page given its url or id. Use
+// FrontPage to get the front page.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.show
+func (c *Course) Page(url string, opts ...Option) (*Page, error) {
+	return getPage(c.client, c.id("courses/%d"), url, opts)
+}
+
+// FrontPage will get the course's front page.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.show_front_page
+func (c *Course) FrontPage(opts ...Option) (*Page, error) {
+	return getFrontPage(c.client, c.id("courses/%d"), opts)
+}
+
+// CreatePage will create a new wiki page in the course.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.create
+func (c *Course) CreatePage(p Page) (*Page, error) {
+	return createPage(c.client, c.id("courses/%d"), &p)
+}
+
+// Pages will get the group's wiki pages.
+func (g *Group) Pages(opts ...Option) ([]*Page, error) {
+	return listPages(g.client, g.id("groups/%d"), opts)
+}
+
+// Page will get a group wiki page given its url or id. Use
+// FrontPage to get the front page.
+func (g *Group) Page(url string, opts ...Option) (*Page, error) {
+	return getPage(g.client, g.id("groups/%d"), url, opts)
+}
+
+// FrontPage will get the group's front page.
+func (g *Group) FrontPage(opts ...Option) (*Page, error) {
+	return getFrontPage(g.client, g.id("groups/%d"), opts)
+}
+
+// CreatePage will create a new wiki page in the group.
+func (g *Group) CreatePage(p Page) (*Page, error) {
+	return createPage(g.client, g.id("groups/%d"), &p)
+}
+
+// Update will send the page's title, body, editing roles and
+// published and front page status to canvas. The page will be
+// updated with the response.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.update
+func (p *Page) Update() error {
+	q, err := query.Values(&pageOptions{*p})
+	if err != nil {
+		return err
+	}
+	return p.update(q)
+}
+
+// Publish will publish the page.
+func (p *Page) Publish() error {
+	return p.update(params{"wiki_page[published]": {"true"}})
+}
+
+// Unpublish will unpublish the page.
+func (p *Page) Unpublish() error {
+	return p.update(params{"wiki_page[published]": {"false"}})
+}
+
+// SetFrontPage will make the page the front page of its course or group.
+func (p *Page) SetFrontPage() error {
+	return p.update(params{"wiki_page[front_page]": {"true"}})
+}
+
+// Delete will delete the page.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.destroy
+func (p *Page) Delete() error {
+	resp, err := delete(p.client, p.endpoint(""), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Revisions will list the page's revisions. The revisions do not include the
+// page body, use Revision to get the full contents.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.revisions
+func (p *Page) Revisions(opts ...Option) (revs []*PageRevision, err error) {
+	ch := make(chan *PageRevision)
+	pager := newPaginatedList(p.client, p.endpoint("/revisions"), func(r io.Reader) error {
+		list := make([]*PageRevision, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, rev := range list {
+			ch <- rev
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case rev := <-ch:
+			revs = append(revs, rev)
+		case err := <-errs:
+			return revs, err
+		}
+	}
+}
+
+// Revision will get a specific revision of the page.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.show_revision
+func (p *Page) Revision(id int, opts ...Option) (*PageRevision, error) {
+	rev := &PageRevision{}
+	return rev, getjson(p.client, rev, optEnc(opts), p.endpoint("/revisions/%d"), id)
+}
+
+// LatestRevision will get the latest revision of the page.
+func (p *Page) LatestRevision(opts ...Option) (*PageRevision, error) {
+	rev := &PageRevision{}
+	return rev, getjson(p.client, rev, optEnc(opts), p.endpoint("/revisions/latest"))
+}
+
+// Revert will revert the page to a previous revision.
+//
+// https://canvas.instructure.com/doc/api/pages.html#method.wiki_pages_api.revert
+func (p *Page) Revert(revision int) error {
+	resp, err := post(p.client, fmt.Sprintf(p.endpoint("/revisions/%d"), revision), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	rev := &PageRevision{}
+	if err = json.NewDecoder(resp.Body).Decode(rev); err != nil {
+		return err
+	}
+	p.Title = rev.Title
+	p.Body = rev.Body
+	p.RevisionID = rev.ID
+	p.UpdatedAt = rev.UpdatedAt
+	return nil
+}
+
+func (p *Page) update(q encoder) error {
+	resp, err := put(p.client, p.endpoint(""), q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(p)
+}
+
+func (p *Page) endpoint(suffix string) string {
+	return fmt.Sprintf("/%s/pages/%s", p.path, p.URL) + suffix
+}
+
+// PageRevision is a revision of a wiki page.
+//
+// https://canvas.instructure.com/doc/api/pages.html#PageRevision
+type PageRevision struct {
+	ID        int       `json:"revision_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Latest    bool      `json:"latest"`
+	EditedBy  *User     `json:"edited_by"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+}
+
+// DiffOp is the type of change found in a diff.
+type DiffOp int
+
+const (
+	// DiffEqual is a line that is unchanged between revisions
+	DiffEqual DiffOp = iota
+	// DiffInsert is a line that was added in the newer revision
+	DiffInsert
+	// DiffDelete is a line that was removed from the older revision
+	DiffDelete
+)
+
+// DiffLine is one line of a diff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+func (dl DiffLine) String() string {
+	switch dl.Op {
+	case DiffInsert:
+		return "+ " + dl.Text
+	case DiffDelete:
+		return "- " + dl.Text
+	default:
+		return "  " + dl.Text
+	}
+}
+
+// DiffRevisions will compare the html bodies of two page revisions line by
+// line. Html tags that are not separated by a newline are treated as separate
+// lines so that pages saved on a single line still give a readable diff.
+func DiffRevisions(older, newer *PageRevision) []DiffLine {
+	return diffLines(splitHTML(older.Body), splitHTML(newer.Body))
+}
+
+func splitHTML(body string) []string {
+	body = strings.Replace(body, "><", ">\n<", -1)
+	lines := strings.Split(body, "\n")
+	res := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// diffLines finds the longest common subsequence of a and b
+// and uses it to build a list of changes.
+func diffLines(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	diff := make([]DiffLine, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{DiffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{DiffDelete, a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{DiffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		diff = append(diff, DiffLine{DiffDelete, a[i]})
+	}
+	for ; j < m; j++ {
+		diff = append(diff, DiffLine{DiffInsert, b[j]})
+	}
+	return diff
+}
+
+func getPage(d doer, ctxpath, url string, opts []Option) (*Page, error) {
+	if url == "" {
+		return nil, errors.New("no page url given")
+	}
+	p := &Page{client: d, path: ctxpath}
+	return p, getjson(d, p, optEnc(opts), "/%s/pages/%s", ctxpath, url)
+}
+
+func getFrontPage(d doer, ctxpath string, opts []Option) (*Page, error) {
+	p := &Page{client: d, path: ctxpath}
+	return p, getjson(d, p, optEnc(opts), "/%s/front_page", ctxpath)
+}
+
+func createPage(d doer, ctxpath string, p *Page) (*Page, error) {
+	q, err := query.Values(&pageOptions{*p})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(d, fmt.Sprintf("/%s/pages", ctxpath), q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	page := &Page{client: d, path: ctxpath}
+	return page, json.NewDecoder(resp.Body).Decode(page)
+}
+
+func listPages(d doer, ctxpath string, opts []Option) (pages []*Page, err error) {
+	ch := make(chan *Page)
+	pager := newPaginatedList(d, fmt.Sprintf("/%s/pages", ctxpath), func(r io.Reader) error {
+		list := make([]*Page, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, p := range list {
+			p.client, p.path = d, ctxpath
+			ch <- p
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case p := <-ch:
+			pages = append(pages, p)
+		case err := <-errs:
+			return pages, err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPages(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/pages", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q.Get("wiki_page[title]"), "Syllabus")
+		is.Equal(q.Get("wiki_page[body]"), "<p>one</p>")
+		w.Write([]byte(`{"page_id":1,"url":"syllabus","title":"Syllabus","body":"<p>one</p>"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/pages/syllabus/revisions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"revision_id":2,"latest":true},{"revision_id":1}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/pages/syllabus/revisions/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Write([]byte(`{"revision_id":3,"title":"Syllabus","body":"<p>one</p>"}`))
+			return
+		}
+		w.Write([]byte(`{"revision_id":1,"body":"<p>one</p><p>two</p>"}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/pages/syllabus/revisions/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"revision_id":2,"latest":true,"body":"<p>one</p>\n<p>three</p>"}`))
+	})
+
+	course := &Course{ID: 1, client: client}
+	page, err := course.CreatePage(Page{Title: "Syllabus", Body: "<p>one</p>"})
+	is.NoErr(err)
+	is.Equal(page.URL, "syllabus")
+	revs, err := page.Revisions()
+	is.NoErr(err)
+	is.Equal(len(revs), 2)
+	old, err := page.Revision(1)
+	is.NoErr(err)
+	latest, err := page.LatestRevision()
+	is.NoErr(err)
+	is.Equal(DiffRevisions(old, latest), []DiffLine{
+		{DiffEqual, "<p>one</p>"},
+		{DiffDelete, "<p>two</p>"},
+		{DiffInsert, "<p>three</p>"},
+	})
+	is.NoErr(page.Revert(1))
+	is.Equal(page.RevisionID, 3)
+}
+
+func TestFrontPage(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/front_page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page_id":1,"url":"home","title":"Home","front_page":true}`))
+	})
+
+	course := &Course{ID: 1, client: client}
+	_, err := course.Page("")
+	is.True(err != nil) // the front page is only returned by FrontPage
+	page, err := course.FrontPage()
+	is.NoErr(err)
+	is.True(page.FrontPage)
+	is.Equal(page.URL, "home")
+}
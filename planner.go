@@ -0,0 +1,419 @@
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// Planner item filters are given when listing planner items.
+var (
+	NewActivityItems Option = Opt("filter", "new_activity")
+	IncompleteItems  Option = Opt("filter", "incomplete_items")
+	CompleteItems    Option = Opt("filter", "complete_items")
+)
+
+// Todos will get the current user's todo's.
+//
+// https://canvas.instructure.com/doc/api/users.html#method.users.todo_items
+func (c *Canvas) Todos(opts ...Option) (todos []TODO, err error) {
+	ch := make(chan TODO)
+	pager := newPaginatedList(c.client, "/users/self/todo", func(r io.Reader) error {
+		list := make([]TODO, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, t := range list {
+			t.client = c.client
+			ch <- t
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case t := <-ch:
+			todos = append(todos, t)
+		case err := <-errs:
+			return todos, err
+		}
+	}
+}
+
+// Todos will get the current user's todo's.
+func Todos(opts ...Option) ([]TODO, error) { return ca.Todos(opts...) }
+
+// TODO is a to-do struct
+type TODO struct {
+	Type              string      `json:"type"`
+	Assignment        *Assignment `json:"assignment"`
+	Quiz              *Quiz       `json:"quiz"`
+	Ignore            string      `json:"ignore"`
+	IgnorePerminantly string      `json:"ignore_permanently"`
+	HTMLURL           string      `json:"html_url"`
+	NeedsGradingCount int         `json:"needs_grading_count"`
+	ContextType       string      `json:"context_type"`
+	ContextID         int         `json:"context_id"`
+	CourseID          int         `json:"course_id"`
+	GroupID           interface{} `json:"group_id"`
+
+	client doer
+}
+
+// Dismiss will hide the to-do item until it changes,
+// ex. a new submission needs grading.
+func (t *TODO) Dismiss() error {
+	return t.ignore(t.Ignore)
+}
+
+// DismissPermanently will hide the to-do item forever.
+func (t *TODO) DismissPermanently() error {
+	return t.ignore(t.IgnorePerminantly)
+}
+
+func (t *TODO) ignore(link string) error {
+	if link == "" {
+		return errors.New("to-do item cannot be ignored")
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	// only the path is used so that the api token is
+	// never sent to a host other than the one configured
+	req := &http.Request{
+		Method: "DELETE",
+		Proto:  "HTTP/1.1",
+		URL: &url.URL{
+			Scheme:   "https",
+			Path:     u.Path,
+			RawQuery: u.RawQuery,
+		},
+	}
+	resp, err := do(t.client, req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// PlannerItem is an item in the current user's planner.
+//
+// https://canvas.instructure.com/doc/api/planner.html
+type PlannerItem struct {
+	ContextType string `json:"context_type"`
+	CourseID    int    `json:"course_id"`
+	GroupID     int    `json:"group_id"`
+	UserID      int    `json:"user_id"`
+	ContextName string `json:"context_name"`
+	// PlannableType is one of "assignment", "quiz", "discussion_topic",
+	// "wiki_page", "planner_note", "calendar_event" or "assessment_request"
+	PlannableType   string             `json:"plannable_type"`
+	PlannableID     int                `json:"plannable_id"`
+	PlannableDate   time.Time          `json:"plannable_date"`
+	Plannable       Plannable          `json:"plannable"`
+	PlannerOverride *PlannerOverride   `json:"planner_override"`
+	NewActivity     bool               `json:"new_activity"`
+	Submissions     PlannerSubmissions `json:"submissions"`
+	HTMLURL         string             `json:"html_url"`
+
+	client doer
+}
+
+// Plannable holds the common fields of the object that a
+// planner item refers to.
+type Plannable struct {
+	ID             int       `json:"id"`
+	Title          string    `json:"title"`
+	DueAt          time.Time `json:"due_at"`
+	TodoDate       time.Time `json:"todo_date"`
+	PointsPossible float64   `json:"points_possible"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PlannerSubmissions is the submission status of a planner item.
+// All fields are false when the item cannot be submitted.
+type PlannerSubmissions struct {
+	Submitted    bool `json:"submitted"`
+	Excused      bool `json:"excused"`
+	Graded       bool `json:"graded"`
+	Late         bool `json:"late"`
+	Missing      bool `json:"missing"`
+	NeedsGrading bool `json:"needs_grading"`
+	HasFeedback  bool `json:"has_feedback"`
+	RedoRequest  bool `json:"redo_request"`
+}
+
+// UnmarshalJSON handles canvas sending false
+// when an item has no submissions.
+func (ps *PlannerSubmissions) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("false")) || bytes.Equal(b, []byte("null")) {
+		*ps = PlannerSubmissions{}
+		return nil
+	}
+	type plannerSubmissions PlannerSubmissions
+	return json.Unmarshal(b, (*plannerSubmissions)(ps))
+}
+
+// PlannerItems will get the current user's planner items. Use DateOpt
+// with "start_date" and "end_date" to set the range and
+// NewActivityItems, IncompleteItems or CompleteItems to filter them.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner.index
+func (c *Canvas) PlannerItems(opts ...Option) (items []*PlannerItem, err error) {
+	ch := make(chan *PlannerItem)
+	pager := newPaginatedList(c.client, "/planner/items", func(r io.Reader) error {
+		list := make([]*PlannerItem, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, item := range list {
+			item.client = c.client
+			if item.PlannerOverride != nil {
+				item.PlannerOverride.client = c.client
+			}
+			ch <- item
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case item := <-ch:
+			items = append(items, item)
+		case err := <-errs:
+			return items, err
+		}
+	}
+}
+
+// PlannerItems will get the current user's planner items.
+func PlannerItems(opts ...Option) ([]*PlannerItem, error) {
+	return ca.PlannerItems(opts...)
+}
+
+// MarkComplete will mark the planner item as done.
+func (pi *PlannerItem) MarkComplete() error {
+	return pi.override(params{"marked_complete": {"true"}})
+}
+
+// MarkIncomplete will mark the planner item as not done.
+func (pi *PlannerItem) MarkIncomplete() error {
+	return pi.override(params{"marked_complete": {"false"}})
+}
+
+// Dismiss will hide the planner item from the
+// opportunities list.
+func (pi *PlannerItem) Dismiss() error {
+	return pi.override(params{"dismissed": {"true"}})
+}
+
+// override will update the item's planner override
+// or create one if it does not exist yet.
+func (pi *PlannerItem) override(q params) error {
+	var (
+		resp *http.Response
+		err  error
+	)
+	if pi.PlannerOverride != nil && pi.PlannerOverride.ID != 0 {
+		resp, err = put(pi.client, fmt.Sprintf("/planner/overrides/%d", pi.PlannerOverride.ID), q)
+	} else {
+		q.Set("plannable_type", pi.PlannableType)
+		q.Set("plannable_id", strconv.Itoa(pi.PlannableID))
+		resp, err = post(pi.client, "/planner/overrides", q)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	o := &PlannerOverride{client: pi.client}
+	if err = json.NewDecoder(resp.Body).Decode(o); err != nil {
+		return err
+	}
+	pi.PlannerOverride = o
+	return nil
+}
+
+// PlannerNote is a to-do note in the current user's planner.
+//
+// https://canvas.instructure.com/doc/api/planner.html#PlannerNote
+type PlannerNote struct {
+	ID       int    `json:"id" url:"-"`
+	Title    string `json:"title" url:"title,omitempty"`
+	Details  string `json:"description" url:"details,omitempty"`
+	UserID   int    `json:"user_id" url:"-"`
+	CourseID int    `json:"course_id" url:"course_id,omitempty"`
+	// WorkflowState is either "active" or "deleted"
+	WorkflowState       string    `json:"workflow_state" url:"-"`
+	TodoDate            time.Time `json:"todo_date" url:"todo_date,omitempty"`
+	LinkedObjectType    string    `json:"linked_object_type" url:"linked_object_type,omitempty"`
+	LinkedObjectID      int       `json:"linked_object_id" url:"linked_object_id,omitempty"`
+	LinkedObjectHTMLURL string    `json:"linked_object_html_url" url:"-"`
+	LinkedObjectURL     string    `json:"linked_object_url" url:"-"`
+
+	client doer
+}
+
+// PlannerNotes will list the current user's planner notes.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_notes.index
+func (c *Canvas) PlannerNotes(opts ...Option) (notes []*PlannerNote, err error) {
+	ch := make(chan *PlannerNote)
+	pager := newPaginatedList(c.client, "/planner_notes", func(r io.Reader) error {
+		list := make([]*PlannerNote, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, n := range list {
+			n.client = c.client
+			ch <- n
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case n := <-ch:
+			notes = append(notes, n)
+		case err := <-errs:
+			return notes, err
+		}
+	}
+}
+
+// PlannerNotes will list the current user's planner notes.
+func PlannerNotes(opts ...Option) ([]*PlannerNote, error) {
+	return ca.PlannerNotes(opts...)
+}
+
+// GetPlannerNote will get a planner note by id.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_notes.show
+func (c *Canvas) GetPlannerNote(id int) (*PlannerNote, error) {
+	n := &PlannerNote{client: c.client}
+	return n, getjson(c.client, n, nil, "/planner_notes/%d", id)
+}
+
+// GetPlannerNote will get a planner note by id.
+func GetPlannerNote(id int) (*PlannerNote, error) { return ca.GetPlannerNote(id) }
+
+// CreatePlannerNote will create a new planner note.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_notes.create
+func (c *Canvas) CreatePlannerNote(note PlannerNote) (*PlannerNote, error) {
+	q, err := query.Values(&note)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := post(c.client, "/planner_notes", q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	n := &PlannerNote{client: c.client}
+	return n, json.NewDecoder(resp.Body).Decode(n)
+}
+
+// CreatePlannerNote will create a new planner note.
+func CreatePlannerNote(note PlannerNote) (*PlannerNote, error) {
+	return ca.CreatePlannerNote(note)
+}
+
+// Update will send the note's title, details, course and
+// to-do date to canvas.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_notes.update
+func (pn *PlannerNote) Update() error {
+	q, err := query.Values(pn)
+	if err != nil {
+		return err
+	}
+	resp, err := put(pn.client, fmt.Sprintf("/planner_notes/%d", pn.ID), q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(pn)
+}
+
+// Delete will delete the planner note.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_notes.destroy
+func (pn *PlannerNote) Delete() error {
+	resp, err := delete(pn.client, fmt.Sprintf("/planner_notes/%d", pn.ID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// PlannerOverride is used to mark a planner item as
+// complete or to dismiss it.
+//
+// https://canvas.instructure.com/doc/api/planner.html#PlannerOverride
+type PlannerOverride struct {
+	ID             int       `json:"id"`
+	PlannableType  string    `json:"plannable_type"`
+	PlannableID    int       `json:"plannable_id"`
+	UserID         int       `json:"user_id"`
+	WorkflowState  string    `json:"workflow_state"`
+	MarkedComplete bool      `json:"marked_complete"`
+	Dismissed      bool      `json:"dismissed"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	DeletedAt      time.Time `json:"deleted_at"`
+
+	client doer
+}
+
+// PlannerOverrides will list the current user's planner overrides.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_overrides.index
+func (c *Canvas) PlannerOverrides(opts ...Option) (overrides []*PlannerOverride, err error) {
+	ch := make(chan *PlannerOverride)
+	pager := newPaginatedList(c.client, "/planner/overrides", func(r io.Reader) error {
+		list := make([]*PlannerOverride, 0, defaultPerPage)
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return err
+		}
+		for _, o := range list {
+			o.client = c.client
+			ch <- o
+		}
+		return nil
+	}, opts)
+	errs := pager.start()
+	for {
+		select {
+		case o := <-ch:
+			overrides = append(overrides, o)
+		case err := <-errs:
+			return overrides, err
+		}
+	}
+}
+
+// PlannerOverrides will list the current user's planner overrides.
+func PlannerOverrides(opts ...Option) ([]*PlannerOverride, error) {
+	return ca.PlannerOverrides(opts...)
+}
+
+// Delete will delete the planner override.
+//
+// https://canvas.instructure.com/doc/api/planner.html#method.planner_overrides.destroy
+func (po *PlannerOverride) Delete() error {
+	resp, err := delete(po.client, fmt.Sprintf("/planner/overrides/%d", po.ID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,81 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPlanner(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	mux.HandleFunc("/api/v1/users/self/todo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"type":"grading","needs_grading_count":3,
+			"ignore":"https://canvas.instructure.com/api/v1/users/self/todo/assignment_1/grading?permanent=0",
+			"ignore_permanently":"https://evil.example.com/api/v1/users/self/todo/assignment_1/grading?permanent=1"}]`))
+	})
+	mux.HandleFunc("/api/v1/users/self/todo/assignment_1/grading", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "DELETE")
+		is.Equal(r.Host, "canvas.instructure.com")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v1/planner/items", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("filter"), "incomplete_items")
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[
+			{"plannable_type":"assignment","plannable_id":4,"plannable":{"id":4,"title":"hw"},
+			 "submissions":{"submitted":true,"late":true}},
+			{"plannable_type":"planner_note","plannable_id":5,"submissions":false,
+			 "planner_override":{"id":6,"marked_complete":false}}]`))
+	})
+	mux.HandleFunc("/api/v1/planner/overrides", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q.Get("plannable_type"), "assignment")
+		is.Equal(q.Get("plannable_id"), "4")
+		is.Equal(q.Get("marked_complete"), "true")
+		w.Write([]byte(`{"id":7,"plannable_id":4,"marked_complete":true}`))
+	})
+	mux.HandleFunc("/api/v1/planner/overrides/6", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		is.Equal(r.URL.Query().Get("dismissed"), "true")
+		w.Write([]byte(`{"id":6,"dismissed":true}`))
+	})
+	mux.HandleFunc("/api/v1/planner_notes", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.URL.Query().Get("details"), "read chapter 2")
+		w.Write([]byte(`{"id":8,"title":"reading","description":"read chapter 2"}`))
+	})
+	mux.HandleFunc("/api/v1/planner_notes/8", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "DELETE")
+	})
+
+	todos, err := Todos()
+	is.NoErr(err)
+	is.Equal(len(todos), 1)
+	is.NoErr(todos[0].Dismiss())
+	// the host of the link is ignored
+	is.NoErr(todos[0].DismissPermanently())
+	is.True((&TODO{}).Dismiss() != nil)
+
+	items, err := PlannerItems(IncompleteItems)
+	is.NoErr(err)
+	is.Equal(len(items), 2)
+	is.True(items[0].Submissions.Late)
+	is.Equal(items[0].Plannable.Title, "hw")
+	is.True(!items[1].Submissions.Submitted)
+	is.NoErr(items[0].MarkComplete())
+	is.Equal(items[0].PlannerOverride.ID, 7)
+	is.NoErr(items[1].Dismiss())
+	is.True(items[1].PlannerOverride.Dismissed)
+
+	note, err := CreatePlannerNote(PlannerNote{Title: "reading", Details: "read chapter 2"})
+	is.NoErr(err)
+	is.Equal(note.Details, "read chapter 2")
+	is.NoErr(note.Delete())
+}
@@ -0,0 +1,74 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Progress is used to track the progress of an
+// asynchronous job in canvas.
+//
+// https://canvas.instructure.com/doc/api/progress.html
+type Progress struct {
+	ID          int    `json:"id"`
+	ContextID   int    `json:"context_id"`
+	ContextType string `json:"context_type"`
+	UserID      int    `json:"user_id"`
+	Tag         string `json:"tag"`
+	// Completion is the percent of the job that is done,
+	// canvas sends fractions like 33.3333
+	Completion float64 `json:"completion"`
+	// WorkflowState will be one of "queued", "running",
+	// "completed", or "failed"
+	WorkflowState string    `json:"workflow_state"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Message       string    `json:"message"`
+	URL           string    `json:"url"`
+	// Results is set by some jobs once they complete.
+	Results json.RawMessage `json:"results"`
+
+	client doer
+}
+
+// Done returns true if the job has either completed or failed.
+func (p *Progress) Done() bool {
+	return p.WorkflowState == "completed" || p.WorkflowState == "failed"
+}
+
+// Refresh will get the latest state of the job.
+//
+// https://canvas.instructure.com/doc/api/progress.html#method.progress.show
+func (p *Progress) Refresh() error {
+	return getjson(p.client, p, nil, "/progress/%d", p.ID)
+}
+
+// Wait will poll the job every interval until it is done. An
+// error is returned if the job fails.
+func (p *Progress) Wait(interval time.Duration) error {
+	return p.wait(interval, 0)
+}
+
+// wait is Wait but it gives up once the timeout has passed
+// if the timeout is positive.
+func (p *Progress) wait(interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !p.Done() {
+		if timeout > 0 && time.Now().After(deadline) {
+			return fmt.Errorf("job %d still %s after %s", p.ID, p.WorkflowState, timeout)
+		}
+		time.Sleep(interval)
+		if err := p.Refresh(); err != nil {
+			return err
+		}
+	}
+	if p.WorkflowState == "failed" {
+		if p.Message == "" {
+			return errors.New("job failed")
+		}
+		return errors.New(p.Message)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package canvas
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestProgress_Wait(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	var polls int
+	mux.HandleFunc("/api/v1/progress/1", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "GET")
+		polls++
+		state := "running"
+		if polls == 3 {
+			state = "completed"
+		}
+		fmt.Fprintf(w, `{"id":1,"workflow_state":%q,"completion":%v}`, state, float64(polls)*33.3333)
+	})
+	p := &Progress{ID: 1, WorkflowState: "queued", client: client}
+	is.NoErr(p.Refresh())
+	is.Equal(p.Completion, 33.3333)
+	is.NoErr(p.Wait(time.Millisecond))
+	is.Equal(polls, 3)
+	is.True(p.Done())
+	is.True(p.Completion > 99.9)
+}
@@ -0,0 +1,177 @@
+package canvas
+
+import (
+	"sort"
+)
+
+// Quota is the file storage quota of a course, group or user in bytes.
+//
+// https://canvas.instructure.com/doc/api/files.html#method.files.api_quota
+type Quota struct {
+	Quota     int64 `json:"quota"`
+	QuotaUsed int64 `json:"quota_used"`
+}
+
+// Available returns the number of bytes left in the quota.
+func (q *Quota) Available() int64 {
+	return q.Quota - q.QuotaUsed
+}
+
+// Quota will get the course's file storage quota.
+func (c *Course) Quota() (*Quota, error) {
+	q := &Quota{}
+	return q, getjson(c.client, q, nil, "/courses/%d/files/quota", c.ID)
+}
+
+// Quota will get the group's file storage quota.
+func (g *Group) Quota() (*Quota, error) {
+	q := &Quota{}
+	return q, getjson(g.client, q, nil, "/groups/%d/files/quota", g.ID)
+}
+
+// Quota will get the user's file storage quota.
+func (u *User) Quota() (*Quota, error) {
+	q := &Quota{}
+	return q, getjson(u.client, q, nil, "/users/%d/files/quota", u.ID)
+}
+
+// UsageReport shows what is using the storage of a course, group or user.
+// Each breakdown is sorted from the largest to the smallest size.
+type UsageReport struct {
+	// Size is the total size of all the files in bytes.
+	Size  int64
+	Files int
+	// ByFolder uses the full folder names as keys. The size of
+	// a folder includes the files in all of its subfolders.
+	ByFolder      []UsageEntry
+	ByContentType []UsageEntry
+	// ByUploader uses the uploader's display name as keys.
+	ByUploader []UsageEntry
+}
+
+// UsageEntry is the storage used by one folder, content type or uploader.
+type UsageEntry struct {
+	Key string
+	// ID is the id of the folder or uploader, zero for content types.
+	ID    int
+	Files int
+	Size  int64
+}
+
+// UsageReport will list all of the course's files and sum their sizes.
+func (c *Course) UsageReport() (*UsageReport, error) {
+	folders, err := c.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := c.ListFiles(IncludeOpt("user"))
+	if err != nil {
+		return nil, err
+	}
+	return newUsageReport(folders, files), nil
+}
+
+// UsageReport will list all of the group's files and sum their sizes.
+func (g *Group) UsageReport() (*UsageReport, error) {
+	folders, err := g.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := g.ListFiles(IncludeOpt("user"))
+	if err != nil {
+		return nil, err
+	}
+	return newUsageReport(folders, files), nil
+}
+
+// UsageReport will list all of the user's files and sum their sizes.
+func (u *User) UsageReport() (*UsageReport, error) {
+	folders, err := u.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+	files, err := u.ListFiles(IncludeOpt("user"))
+	if err != nil {
+		return nil, err
+	}
+	return newUsageReport(folders, files), nil
+}
+
+type usageKey struct {
+	key string
+	id  int
+}
+
+type usageTally map[usageKey]*UsageEntry
+
+func (t usageTally) add(key string, id int, size int64) {
+	k := usageKey{key, id}
+	e, ok := t[k]
+	if !ok {
+		e = &UsageEntry{Key: key, ID: id}
+		t[k] = e
+	}
+	e.Files++
+	e.Size += size
+}
+
+func (t usageTally) sorted() []UsageEntry {
+	entries := make([]UsageEntry, 0, len(t))
+	for _, e := range t {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size == entries[j].Size {
+			if entries[i].Key == entries[j].Key {
+				return entries[i].ID < entries[j].ID
+			}
+			return entries[i].Key < entries[j].Key
+		}
+		return entries[i].Size > entries[j].Size
+	})
+	return entries
+}
+
+func newUsageReport(folders []*Folder, files []*File) *UsageReport {
+	byID := make(map[int]*Folder, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+	var (
+		report     = &UsageReport{}
+		byFolder   = usageTally{}
+		byType     = usageTally{}
+		byUploader = usageTally{}
+	)
+	for _, f := range files {
+		size := int64(f.Size)
+		report.Size += size
+		report.Files++
+
+		// count the file in every folder up to the root
+		for id, seen := f.FolderID, 0; id != 0 && seen < len(folders); seen++ {
+			folder, ok := byID[id]
+			if !ok {
+				break
+			}
+			byFolder.add(folder.FullName, folder.ID, size)
+			id = folder.ParentFolderID
+		}
+
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "unknown"
+		}
+		byType.add(contentType, 0, size)
+
+		if f.User != nil {
+			byUploader.add(f.User.DisplayName, f.User.ID, size)
+		} else {
+			byUploader.add("unknown", 0, size)
+		}
+	}
+	report.ByFolder = byFolder.sorted()
+	report.ByContentType = byType.sorted()
+	report.ByUploader = byUploader.sorted()
+	return report
+}
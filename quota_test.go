@@ -0,0 +1,65 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestQuota(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	for _, p := range []string{"/api/v1/courses/1/files/quota", "/api/v1/groups/1/files/quota", "/api/v1/users/1/files/quota"} {
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"quota":524288000,"quota_used":402653184}`))
+		})
+	}
+	q, err := (&Course{ID: 1, client: client}).Quota()
+	is.NoErr(err)
+	is.Equal(q.Quota, int64(524288000))
+	is.Equal(q.Available(), int64(524288000-402653184))
+	_, err = (&Group{ID: 1, client: client}).Quota()
+	is.NoErr(err)
+	_, err = (&User{ID: 1, client: client}).Quota()
+	is.NoErr(err)
+}
+
+func TestCourse_UsageReport(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[
+			{"id":1,"full_name":"course files"},
+			{"id":2,"parent_folder_id":1,"full_name":"course files/videos"},
+			{"id":3,"parent_folder_id":2,"full_name":"course files/videos/week1"}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query()["include[]"], []string{"user"})
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[
+			{"id":10,"folder_id":1,"size":100,"content-type":"application/pdf","user":{"id":5,"display_name":"Teacher"}},
+			{"id":11,"folder_id":3,"size":5000,"content-type":"video/mp4","user":{"id":5,"display_name":"Teacher"}},
+			{"id":12,"folder_id":2,"size":3000,"content-type":"video/mp4","user":{"id":6,"display_name":"TA"}},
+			{"id":13,"folder_id":1,"size":10,"content-type":"text/plain"}]`))
+	})
+
+	report, err := (&Course{ID: 1, client: client}).UsageReport()
+	is.NoErr(err)
+	is.Equal(report.Size, int64(8110))
+	is.Equal(report.Files, 4)
+	is.Equal(report.ByFolder, []UsageEntry{
+		{Key: "course files", ID: 1, Files: 4, Size: 8110},
+		{Key: "course files/videos", ID: 2, Files: 2, Size: 8000},
+		{Key: "course files/videos/week1", ID: 3, Files: 1, Size: 5000},
+	})
+	is.Equal(report.ByContentType[0], UsageEntry{Key: "video/mp4", Files: 2, Size: 8000})
+	is.Equal(report.ByUploader, []UsageEntry{
+		{Key: "Teacher", ID: 5, Files: 2, Size: 5100},
+		{Key: "TA", ID: 6, Files: 1, Size: 3000},
+		{Key: "unknown", Files: 1, Size: 10},
+	})
+}
@@ -0,0 +1,186 @@
+package canvas
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrSearchTermTooShort is returned when a FileQuery's search term is
+// too short for canvas to search with.
+var ErrSearchTermTooShort = errors.New("search term must be at least 2 characters")
+
+// File sort orders used by FileQuery.Sort
+const (
+	FileSortName        = "name"
+	FileSortSize        = "size"
+	FileSortCreatedAt   = "created_at"
+	FileSortUpdatedAt   = "updated_at"
+	FileSortContentType = "content_type"
+	FileSortUser        = "user"
+)
+
+// FileQuery is used to search and filter files.
+//
+// https://canvas.instructure.com/doc/api/files.html#method.files.api_index
+type FileQuery struct {
+	// SearchTerm is part of the file name. Canvas needs at
+	// least two characters, SearchFiles returns ErrSearchTermTooShort
+	// for anything shorter.
+	SearchTerm string
+	// ContentTypes limits the files to these content types
+	// or content type prefixes, ex. "image" or "application/pdf".
+	ContentTypes        []string
+	ExcludeContentTypes []string
+	// Sort is one of the FileSort constants, defaults to FileSortName.
+	Sort string
+	// Order is either "asc" or "desc".
+	Order string
+	// Include adds extra fields to the files, ex. "user" or "usage_rights".
+	Include []string
+}
+
+// Options returns the query as a list of Options.
+func (q *FileQuery) Options() []Option {
+	var opts []Option
+	if q.SearchTerm != "" {
+		opts = append(opts, Opt("search_term", q.SearchTerm))
+	}
+	if len(q.ContentTypes) > 0 {
+		opts = append(opts, ContentTypes(q.ContentTypes...))
+	}
+	if len(q.ExcludeContentTypes) > 0 {
+		opts = append(opts, ArrayOpt("exclude_content_types", q.ExcludeContentTypes...))
+	}
+	if q.Sort != "" {
+		opts = append(opts, Opt("sort", q.Sort))
+	}
+	if q.Order != "" {
+		opts = append(opts, Opt("order", q.Order))
+	}
+	if len(q.Include) > 0 {
+		opts = append(opts, IncludeOpt(q.Include...))
+	}
+	return opts
+}
+
+func (q *FileQuery) validate() error {
+	if q.SearchTerm != "" && len([]rune(q.SearchTerm)) < 2 {
+		return ErrSearchTermTooShort
+	}
+	return nil
+}
+
+// SearchFiles will list the course's files that match the query.
+func (c *Course) SearchFiles(q FileQuery) ([]*File, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return c.ListFiles(q.Options()...)
+}
+
+// SearchFiles will list the group's files that match the query.
+func (g *Group) SearchFiles(q FileQuery) ([]*File, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return g.ListFiles(q.Options()...)
+}
+
+// SearchFiles will list the user's files that match the query.
+func (u *User) SearchFiles(q FileQuery) ([]*File, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return u.ListFiles(q.Options()...)
+}
+
+// SearchFiles will list the files in the folder that match the query.
+func (f *Folder) SearchFiles(q FileQuery) ([]*File, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return f.ListFiles(q.Options()...)
+}
+
+// SearchFiles will search the files of every active course of the current
+// user. The results of each course are merged and sorted using the query's
+// sort order. If some courses fail the files that were found are returned
+// along with the first error.
+func (c *Canvas) SearchFiles(q FileQuery) ([]*File, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	courses, err := c.Courses(ActiveCourses)
+	if err != nil {
+		return nil, err
+	}
+	return searchCourseFiles(courses, q)
+}
+
+// SearchFiles will search the files of every active course of the current user.
+func SearchFiles(q FileQuery) ([]*File, error) {
+	return ca.SearchFiles(q)
+}
+
+func searchCourseFiles(courses []*Course, q FileQuery) ([]*File, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		files    []*File
+		firstErr error
+		sem      = make(chan struct{}, defaultConcurrency)
+	)
+	for _, course := range courses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(course *Course) {
+			defer func() { <-sem; wg.Done() }()
+			found, err := course.SearchFiles(q)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			files = append(files, found...)
+		}(course)
+	}
+	wg.Wait()
+	sortFiles(files, q.Sort, q.Order)
+	return files, firstErr
+}
+
+func sortFiles(files []*File, by, order string) {
+	var less func(a, b *File) bool
+	switch by {
+	case FileSortSize:
+		less = func(a, b *File) bool { return a.Size < b.Size }
+	case FileSortCreatedAt:
+		less = func(a, b *File) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case FileSortUpdatedAt:
+		less = func(a, b *File) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	case FileSortContentType:
+		less = func(a, b *File) bool { return a.ContentType < b.ContentType }
+	case FileSortUser:
+		less = func(a, b *File) bool { return fileUserName(a) < fileUserName(b) }
+	default:
+		less = func(a, b *File) bool {
+			return strings.ToLower(a.Name()) < strings.ToLower(b.Name())
+		}
+	}
+	desc := order == "desc"
+	sort.SliceStable(files, func(i, j int) bool {
+		if desc {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+}
+
+func fileUserName(f *File) string {
+	if f.User == nil {
+		return ""
+	}
+	return f.User.DisplayName
+}
@@ -0,0 +1,71 @@
+package canvas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSearchFiles(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer swapCanvas(&Canvas{client: client})()
+
+	mux.HandleFunc("/api/v1/courses", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("enrollment_state"), "active")
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":1},{"id":2}]`))
+	})
+	check := func(r *http.Request) {
+		q := r.URL.Query()
+		is.Equal(q.Get("search_term"), "week")
+		is.Equal(q["content_types[]"], []string{"application/pdf", "image"})
+		is.Equal(q["exclude_content_types[]"], []string{"image/gif"})
+		is.Equal(q.Get("sort"), FileSortSize)
+		is.Equal(q.Get("order"), "desc")
+		is.Equal(q["include[]"], []string{"user"})
+	}
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":10,"display_name":"week1.pdf","size":30},{"id":11,"display_name":"week2.pdf","size":10}]`))
+	})
+	mux.HandleFunc("/api/v1/courses/2/files", func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		w.Header().Set("Link", testLinkHeader)
+		w.Write([]byte(`[{"id":20,"display_name":"week1.png","size":20}]`))
+	})
+
+	files, err := SearchFiles(FileQuery{
+		SearchTerm:          "week",
+		ContentTypes:        []string{"application/pdf", "image"},
+		ExcludeContentTypes: []string{"image/gif"},
+		Sort:                FileSortSize,
+		Order:               "desc",
+		Include:             []string{"user"},
+	})
+	is.NoErr(err)
+	is.Equal(len(files), 3)
+	is.Equal(files[0].ID, 10)
+	is.Equal(files[1].ID, 20)
+	is.Equal(files[2].ID, 11)
+
+	sortFiles(files, "", "")
+	is.Equal(files[0].Name(), "week1.pdf")
+	is.Equal(files[1].Name(), "week1.png")
+}
+
+func TestSearchFiles_ShortTerm(t *testing.T) {
+	is := is.New(t)
+	client, _, server := testServer()
+	defer server.Close()
+
+	// canvas responds with 400 so no request should be sent
+	c := &Canvas{client: client}
+	_, err := c.SearchFiles(FileQuery{SearchTerm: "a"})
+	is.Equal(err, ErrSearchTermTooShort)
+	_, err = (&Course{ID: 1, client: client}).SearchFiles(FileQuery{SearchTerm: "a"})
+	is.Equal(err, ErrSearchTermTooShort)
+}
@@ -0,0 +1,320 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// UploadStatus is the outcome of uploading one local file or folder.
+type UploadStatus string
+
+// Upload statuses
+const (
+	// UploadUploaded means the file was new or had changed and was uploaded.
+	UploadUploaded UploadStatus = "uploaded"
+	// UploadSkipped means the file was already up to date in canvas.
+	UploadSkipped UploadStatus = "skipped"
+	// UploadFailed means there was an error, see UploadResult.Err.
+	UploadFailed UploadStatus = "failed"
+	// UploadCreatedFolder means a missing folder was created.
+	UploadCreatedFolder UploadStatus = "created folder"
+)
+
+// UploadDirOptions changes the behavior of Folder.UploadDir.
+type UploadDirOptions struct {
+	// OnDuplicate is what canvas will do when a file with the same name
+	// already exists. Can be "overwrite" or "rename", defaults to
+	// "overwrite".
+	OnDuplicate string
+	// Force will upload every file even if it has not changed.
+	Force bool
+	// IncludeHidden will upload files and folders that start with a '.',
+	// these are skipped by default.
+	IncludeHidden bool
+}
+
+// UploadResult is the result of uploading one local file or folder.
+type UploadResult struct {
+	// Path is the slash separated path relative to the local directory.
+	Path   string
+	Status UploadStatus
+	// File is the canvas file, nil for folders or failed uploads.
+	File *File
+	Err  error
+}
+
+// UploadDir will walk a local directory and upload its contents into the
+// folder. Missing folders are created and files are only uploaded when
+// they are not in canvas yet or when their size or modification time
+// differ from the canvas copy. Errors for a single file do not stop the
+// upload and are reported in the results.
+func (f *Folder) UploadDir(dir string, opts *UploadDirOptions) ([]UploadResult, error) {
+	if opts == nil {
+		opts = &UploadDirOptions{}
+	}
+	onDup := opts.OnDuplicate
+	if onDup == "" {
+		onDup = "overwrite"
+	}
+	var (
+		results []UploadResult
+		folders = map[string]*folderContents{".": {folder: f}}
+	)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if !opts.IncludeHidden && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		parent, ok := folders[filepath.Dir(rel)]
+		if !ok {
+			// the parent folder failed so everything in it is skipped
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		res := UploadResult{Path: filepath.ToSlash(rel)}
+
+		if info.IsDir() {
+			sub, created, err := parent.subfolder(info.Name())
+			if err != nil {
+				res.Status, res.Err = UploadFailed, err
+				results = append(results, res)
+				return filepath.SkipDir
+			}
+			folders[rel] = &folderContents{folder: sub}
+			if created {
+				res.Status = UploadCreatedFolder
+				results = append(results, res)
+			}
+			return nil
+		}
+
+		remote, err := parent.file(info.Name())
+		if err != nil {
+			res.Status, res.Err = UploadFailed, err
+			results = append(results, res)
+			return nil
+		}
+		if !opts.Force && remote != nil && !localChanged(info, remote) {
+			res.Status, res.File = UploadSkipped, remote
+			results = append(results, res)
+			return nil
+		}
+		res.File, res.Err = uploadLocalFile(parent.folder, p, info, onDup)
+		if res.Err != nil {
+			res.Status = UploadFailed
+		} else {
+			res.Status = UploadUploaded
+		}
+		results = append(results, res)
+		return nil
+	})
+	return results, err
+}
+
+// folderContents lazily caches the contents of a canvas folder.
+type folderContents struct {
+	folder  *Folder
+	files   map[string]*File
+	folders map[string]*Folder
+}
+
+func (fc *folderContents) subfolder(name string) (*Folder, bool, error) {
+	if fc.folders == nil {
+		list, err := fc.folder.ListFolders()
+		if err != nil {
+			return nil, false, err
+		}
+		fc.folders = make(map[string]*Folder, len(list))
+		for _, sub := range list {
+			fc.folders[sub.Name()] = sub
+		}
+	}
+	if sub, ok := fc.folders[name]; ok {
+		return sub, false, nil
+	}
+	sub, err := fc.folder.CreateFolder(name)
+	if err != nil {
+		return nil, false, err
+	}
+	fc.folders[name] = sub
+	return sub, true, nil
+}
+
+func (fc *folderContents) file(name string) (*File, error) {
+	if fc.files == nil {
+		list, err := fc.folder.ListFiles()
+		if err != nil {
+			return nil, err
+		}
+		fc.files = make(map[string]*File, len(list))
+		for _, file := range list {
+			fc.files[file.Name()] = file
+		}
+	}
+	return fc.files[name], nil
+}
+
+func localChanged(info os.FileInfo, remote *File) bool {
+	return info.Size() != int64(remote.Size) || info.ModTime().After(remote.UpdatedAt)
+}
+
+func uploadLocalFile(folder *Folder, p string, info os.FileInfo, onDuplicate string) (*File, error) {
+	fh, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return folder.UploadFile(
+		info.Name(), fh,
+		Opt("on_duplicate", onDuplicate),
+		Opt("size", int(info.Size())),
+	)
+}
+
+// URLUploadPollInterval is how often the progress of an
+// upload by url is checked.
+var URLUploadPollInterval = time.Second
+
+// URLUploadTimeout is how long to wait for canvas to finish
+// an upload by url before giving up.
+var URLUploadTimeout = 5 * time.Minute
+
+// UploadFromURL will have canvas download a file from a public url into
+// the folder. The file name defaults to the last element of the url path
+// and can be changed with Opt("name", "filename"). This function blocks
+// until canvas has finished downloading the file or URLUploadTimeout
+// has passed.
+//
+// https://canvas.instructure.com/doc/api/file.file_uploads.html#uploading-via-url
+func (f *Folder) UploadFromURL(fileURL string, opts ...Option) (*File, error) {
+	params := &fileUploadParams{ParentFolderID: f.ID}
+	return uploadFromURL(f.client, fileURL, fmt.Sprintf("/folders/%d/files", f.ID), params, opts)
+}
+
+// UploadFromURL will have canvas download a file from a public url into
+// the course's files.
+func (c *Course) UploadFromURL(fileURL string, opts ...Option) (*File, error) {
+	return uploadFromURL(c.client, fileURL, c.id("/courses/%d/files"), &fileUploadParams{}, opts)
+}
+
+// UploadFromURL will have canvas download a file from a public url into
+// the current user's files.
+func (c *Canvas) UploadFromURL(fileURL string, opts ...Option) (*File, error) {
+	return uploadFromURL(c.client, fileURL, "/users/self/files", &fileUploadParams{}, opts)
+}
+
+// UploadFromURL will have canvas download a file from a public url into
+// the current user's files.
+func UploadFromURL(fileURL string, opts ...Option) (*File, error) {
+	return ca.UploadFromURL(fileURL, opts...)
+}
+
+type urlUpload struct {
+	UploadURL    string            `json:"upload_url"`
+	UploadParams map[string]string `json:"upload_params"`
+	Progress     *Progress         `json:"progress"`
+}
+
+func uploadFromURL(d doer, fileURL, endpoint string, params *fileUploadParams, opts []Option) (*File, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("cannot upload from %q: url must be http or https", fileURL)
+	}
+	params.URL = fileURL
+	params.Name = path.Base(u.Path)
+	params.setOptions(opts)
+	if params.Name == "" || params.Name == "/" || params.Name == "." {
+		return nil, errors.New("empty filename")
+	}
+
+	resp, err := post(d, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	up := &urlUpload{}
+	if err = json.NewDecoder(resp.Body).Decode(up); err != nil {
+		return nil, err
+	}
+	if up.Progress == nil {
+		// some instances need the upload params sent to the
+		// upload url before the download starts
+		if up.Progress, err = up.start(d); err != nil {
+			return nil, err
+		}
+	}
+	up.Progress.client = d
+	if err = up.Progress.wait(URLUploadPollInterval, URLUploadTimeout); err != nil {
+		return nil, err
+	}
+	var results struct {
+		ID int `json:"id"`
+	}
+	if err = json.Unmarshal(up.Progress.Results, &results); err != nil || results.ID == 0 {
+		return nil, fmt.Errorf("upload from %q finished without a file", fileURL)
+	}
+	file := &File{client: d}
+	return file, getjson(d, file, nil, "/files/%d", results.ID)
+}
+
+func (up *urlUpload) start(d doer) (*Progress, error) {
+	if up.UploadURL == "" {
+		return nil, errors.New("canvas did not return an upload url")
+	}
+	u, err := url.Parse(up.UploadURL)
+	if err != nil {
+		return nil, err
+	}
+	form := make(url.Values, len(up.UploadParams))
+	for k, v := range up.UploadParams {
+		form.Set(k, v)
+	}
+	body := form.Encode()
+	req := &http.Request{
+		Method:        "POST",
+		URL:           u,
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header: http.Header{
+			"Content-Type": {"application/x-www-form-urlencoded"}},
+	}
+	resp, err := do(d, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	res := &urlUpload{}
+	if err = json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, err
+	}
+	if res.Progress == nil {
+		return nil, errors.New("canvas did not return the upload progress")
+	}
+	return res.Progress, nil
+}
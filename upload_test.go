@@ -0,0 +1,321 @@
+package canvas
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestFolder_UploadDir(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "canvas-upload")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "week1"), 0755))
+	is.NoErr(os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "syllabus.txt"), []byte("syllabus"), 0644))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "unchanged.txt"), []byte("same"), 0644))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "week1", "slides.txt"), []byte("slides"), 0644))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644))
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	is.NoErr(os.Chtimes(filepath.Join(dir, "unchanged.txt"), old, old))
+
+	uploads := map[string]string{}
+	mux.HandleFunc("/api/v1/folders/1/folders", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(`[]`))
+		case "POST":
+			is.Equal(r.URL.Query().Get("name"), "week1")
+			w.Write([]byte(`{"id":2,"name":"week1","parent_folder_id":1}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(`[
+				{"id":10,"display_name":"syllabus.txt","size":3,"updated_at":"2020-01-01T00:00:00Z"},
+				{"id":11,"display_name":"unchanged.txt","size":4,"updated_at":"2020-02-01T00:00:00Z"}]`))
+		case "POST":
+			is.Equal(r.URL.Query().Get("on_duplicate"), "rename")
+			w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{},"file_param":"file"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/folders/2/files", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(`[]`))
+		case "POST":
+			w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{},"file_param":"file"}`))
+		}
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		is.NoErr(err)
+		b, _ := ioutil.ReadAll(f)
+		uploads[h.Filename] = string(b)
+		w.Write([]byte(`{"id":20,"display_name":"` + h.Filename + `"}`))
+	})
+
+	folder := &Folder{ID: 1, client: client}
+	results, err := folder.UploadDir(dir, &UploadDirOptions{OnDuplicate: "rename"})
+	is.NoErr(err)
+	status := map[string]UploadStatus{}
+	for _, res := range results {
+		is.NoErr(res.Err)
+		status[res.Path] = res.Status
+	}
+	is.Equal(len(results), 4)
+	is.Equal(status["syllabus.txt"], UploadUploaded)
+	is.Equal(status["unchanged.txt"], UploadSkipped)
+	is.Equal(status["week1"], UploadCreatedFolder)
+	is.Equal(status["week1/slides.txt"], UploadUploaded)
+	is.Equal(uploads, map[string]string{"syllabus.txt": "syllabus", "slides.txt": "slides"})
+}
+
+func TestStreamingUpload(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{"key":"value"},"file_param":"file"}`))
+	})
+	var lengths []int64
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		lengths = append(lengths, r.ContentLength)
+		is.Equal(r.FormValue("key"), "value")
+		f, h, err := r.FormFile("file")
+		is.NoErr(err)
+		b, _ := ioutil.ReadAll(f)
+		fmt.Fprintf(w, `{"id":20,"display_name":%q,"size":%d}`, h.Filename, len(b))
+	})
+
+	folder := &Folder{ID: 1, client: client}
+	content := strings.Repeat("lecture ", 4096)
+	var sent, total int64
+	file, err := folder.UploadFile(
+		"lecture.txt", strings.NewReader(content),
+		UploadProgress(func(s, t int64) { sent, total = s, t }),
+	)
+	is.NoErr(err)
+	is.Equal(file.Size, len(content))
+	is.Equal(sent, int64(len(content)))
+	is.Equal(total, int64(len(content)))
+	is.True(lengths[0] > int64(len(content))) // length is known
+
+	// unknown size is sent chunked
+	file, err = folder.UploadFile("lecture.txt", ioutil.NopCloser(strings.NewReader(content)))
+	is.NoErr(err)
+	is.Equal(file.Size, len(content))
+	is.Equal(lengths[1], int64(-1))
+
+	// the size of the reader is used over an out of date size param
+	file, err = folder.UploadFile("lecture.txt", strings.NewReader(content), Opt("size", 10))
+	is.NoErr(err)
+	is.Equal(file.Size, len(content))
+
+	// a file's size is what is left after its current offset
+	tmp, err := ioutil.TempFile("", "canvas-upload")
+	is.NoErr(err)
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	_, err = tmp.WriteString(content)
+	is.NoErr(err)
+	_, err = tmp.Seek(8, io.SeekStart)
+	is.NoErr(err)
+	file, err = folder.UploadFile("lecture.txt", tmp)
+	is.NoErr(err)
+	is.Equal(file.Size, len(content)-8)
+
+	// the upload does not start until the first write
+	unused := newFileWriter(client, "/folders/1/files", newFileUploadParams("unused.txt", nil), &File{})
+	is.True(unused.pw == nil)
+
+	wc := newFileWriter(client, "/folders/1/files", newFileUploadParams("notes.txt", nil), &File{})
+	for i := 0; i < 10; i++ {
+		_, err = io.WriteString(wc, "notes\n")
+		is.NoErr(err)
+	}
+	is.NoErr(wc.Close())
+	is.Equal(wc.file.Size, 60)
+	is.Equal(wc.file.DisplayName, "notes.txt")
+}
+
+func TestUploadFromURL(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+	defer func(d time.Duration) { URLUploadPollInterval = d }(URLUploadPollInterval)
+	URLUploadPollInterval = time.Millisecond
+	defer func(d time.Duration) { URLUploadTimeout = d }(URLUploadTimeout)
+	URLUploadTimeout = 20 * time.Millisecond
+
+	var polls int
+	mux.HandleFunc("/api/v1/folders/1/files", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		q := r.URL.Query()
+		is.Equal(q.Get("url"), "https://example.com/files/slides.pdf")
+		is.Equal(q.Get("name"), "slides.pdf")
+		is.Equal(q.Get("parent_folder_id"), "1")
+		w.Write([]byte(`{"progress":{"id":5,"workflow_state":"queued"}}`))
+	})
+	mux.HandleFunc("/api/v1/courses/2/files", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("name"), "renamed.pdf")
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/url-upload","upload_params":{"key":"value"}}`))
+	})
+	mux.HandleFunc("/url-upload", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		is.Equal(r.FormValue("key"), "value")
+		w.Write([]byte(`{"progress":{"id":5,"workflow_state":"running"}}`))
+	})
+	mux.HandleFunc("/api/v1/progress/5", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls%2 == 1 {
+			w.Write([]byte(`{"id":5,"workflow_state":"running","completion":50}`))
+			return
+		}
+		w.Write([]byte(`{"id":5,"workflow_state":"completed","results":{"id":30}}`))
+	})
+	mux.HandleFunc("/api/v1/files/30", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":30,"display_name":"slides.pdf","folder_id":1}`))
+	})
+	mux.HandleFunc("/api/v1/progress/6", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":6,"workflow_state":"failed","message":"could not download file"}`))
+	})
+	mux.HandleFunc("/api/v1/users/self/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"progress":{"id":6,"workflow_state":"queued"}}`))
+	})
+	mux.HandleFunc("/api/v1/folders/2/files", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"progress":{"id":7,"workflow_state":"queued"}}`))
+	})
+	mux.HandleFunc("/api/v1/progress/7", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":7,"workflow_state":"queued"}`)) // never finishes
+	})
+
+	folder := &Folder{ID: 1, client: client}
+	file, err := folder.UploadFromURL("https://example.com/files/slides.pdf")
+	is.NoErr(err)
+	is.Equal(file.ID, 30)
+	is.Equal(polls, 2)
+
+	course := &Course{ID: 2, client: client}
+	file, err = course.UploadFromURL("https://example.com/files/slides.pdf", Opt("name", "renamed.pdf"))
+	is.NoErr(err)
+	is.Equal(file.ID, 30)
+
+	c := &Canvas{client: client}
+	_, err = c.UploadFromURL("https://example.com/missing.pdf")
+	is.True(err != nil)
+	is.Equal(err.Error(), "could not download file")
+	_, err = c.UploadFromURL("file:///etc/passwd")
+	is.True(err != nil)
+
+	stuck := &Folder{ID: 2, client: client}
+	_, err = stuck.UploadFromURL("https://example.com/files/slow.pdf")
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "still queued"))
+}
+
+func TestFile_Replace(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	var uploaded string
+	mux.HandleFunc("/api/v1/folders/3/files", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		is.Equal(q.Get("name"), "syllabus.pdf")
+		is.Equal(q.Get("on_duplicate"), "overwrite")
+		is.Equal(q.Get("parent_folder_id"), "3")
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{},"file_param":"file"}`))
+	})
+	mux.HandleFunc("/api/v1/folders/3", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":3,"context_type":"Course","context_id":1}`))
+	})
+	mux.HandleFunc("/api/v1/courses/1/files", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		is.Equal(q.Get("parent_folder_id"), "3")
+		is.Equal(q.Get("on_duplicate"), "overwrite")
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{},"file_param":"file"}`))
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		is.NoErr(err)
+		b, _ := ioutil.ReadAll(f)
+		uploaded = string(b)
+		fmt.Fprintf(w, `{"id":10,"folder_id":3,"display_name":%q,"filename":%q,"size":%d}`, h.Filename, h.Filename, len(b))
+	})
+
+	file := &File{ID: 10, FolderID: 3, DisplayName: "syllabus.pdf", Filename: "syllabus.pdf", Size: 3, client: client}
+	is.NoErr(file.Replace(strings.NewReader("fixed syllabus")))
+	is.Equal(uploaded, "fixed syllabus")
+	is.Equal(file.ID, 10)
+	is.Equal(file.Size, len("fixed syllabus"))
+
+	// the write closer should upload into the file's folder
+	wc, err := file.AsWriteCloser()
+	is.NoErr(err)
+	_, err = io.WriteString(wc, "fixed again")
+	is.NoErr(err)
+	is.NoErr(wc.Close())
+	is.Equal(uploaded, "fixed again")
+	is.Equal(file.Size, len("fixed again"))
+
+	is.True((&File{DisplayName: "x"}).Replace(strings.NewReader("")) != nil)
+}
+
+func TestContentType(t *testing.T) {
+	is := is.New(t)
+	is.Equal(filenameContentType("README"), "") // should not panic
+	is.Equal(filenameContentType("notes.PDF"), "application/pdf")
+	is.Equal(filenameContentType("page.html"), "text/html")
+	is.Equal(filenameContentType("style.css"), "text/css") // from the mime package
+
+	RegisterContentType("ipynb", "application/x-ipynb+json")
+	defer func() {
+		contentTypes.Lock()
+		contentTypes.overrides = map[string]string{}
+		contentTypes.Unlock()
+	}()
+	is.Equal(filenameContentType("lab1.ipynb"), "application/x-ipynb+json")
+
+	ct, r, err := detectContentType("image", strings.NewReader("\x89PNG\x0D\x0A\x1A\x0Arest of the image"))
+	is.NoErr(err)
+	is.Equal(ct, "image/png")
+	b, err := ioutil.ReadAll(r)
+	is.NoErr(err)
+	is.Equal(string(b), "\x89PNG\x0D\x0A\x1A\x0Arest of the image")
+	ct, _, err = detectContentType("data", bytes.NewReader([]byte{0, 1, 2, 3}))
+	is.NoErr(err)
+	is.Equal(ct, "")
+
+	client, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/api/v1/courses/1/assignments/2/submissions/self/files", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("content_type"), "text/plain")
+		is.Equal(r.URL.Query().Get("size"), "11")
+		w.Write([]byte(`{"upload_url":"https://upload.example.com/upload","upload_params":{},"file_param":"file"}`))
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"display_name":"Makefile"}`))
+	})
+	a := &Assignment{ID: 2, CourseID: 1, client: client}
+	_, err = a.SubmitFile("Makefile", strings.NewReader("all: build\n"))
+	is.NoErr(err)
+}
@@ -0,0 +1,167 @@
+package canvas
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/harrybrwn/go-querystring/query"
+)
+
+// Usage right justifications
+const (
+	JustificationOwnCopyright     = "own_copyright"
+	JustificationUsedByPermission = "used_by_permission"
+	JustificationFairUse          = "fair_use"
+	JustificationPublicDomain     = "public_domain"
+	JustificationCreativeCommons  = "creative_commons"
+)
+
+// UsageRights is the copyright and license information of a file.
+//
+// https://canvas.instructure.com/doc/api/files.html#UsageRights
+type UsageRights struct {
+	// UseJustification is one of the Justification constants.
+	UseJustification string `json:"use_justification" url:"use_justification"`
+	LegalCopyright   string `json:"legal_copyright" url:"legal_copyright,omitempty"`
+	// License is the id of a License, only used with creative commons.
+	License     string `json:"license" url:"license,omitempty"`
+	LicenseName string `json:"license_name" url:"-"`
+	Message     string `json:"message" url:"-"`
+	FileIDs     []int  `json:"file_ids" url:"-"`
+}
+
+// License is a content license that can be used in UsageRights.
+type License struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// usageRightsBatchSize is the max number of files and folders
+// sent in one request.
+const usageRightsBatchSize = 100
+
+type usageRightsOptions struct {
+	UsageRights `url:"usage_rights"`
+}
+
+// SetUsageRights will set the usage rights of files and folders in the
+// course. Setting usage rights on a folder sets them on every file in the
+// folder. Pass Opt("publish", true) to also publish the files.
+//
+// https://canvas.instructure.com/doc/api/files.html#method.usage_rights.set_usage_rights
+func (c *Course) SetUsageRights(rights UsageRights, objs []FileObj, opts ...Option) (*UsageRights, error) {
+	return setUsageRights(c.client, c.id("/courses/%d/usage_rights"), rights, objs, opts)
+}
+
+// RemoveUsageRights will remove the usage rights of files and folders in the course.
+//
+// https://canvas.instructure.com/doc/api/files.html#method.usage_rights.remove_usage_rights
+func (c *Course) RemoveUsageRights(objs []FileObj) error {
+	return removeUsageRights(c.client, c.id("/courses/%d/usage_rights"), objs)
+}
+
+// Licenses will list the licenses that can be used for the course's files.
+//
+// https://canvas.instructure.com/doc/api/files.html#method.usage_rights.licenses
+func (c *Course) Licenses() ([]License, error) {
+	return licenses(c.client, c.id("/courses/%d/content_licenses"))
+}
+
+// SetUsageRights will set the usage rights of files and folders in the group.
+func (g *Group) SetUsageRights(rights UsageRights, objs []FileObj, opts ...Option) (*UsageRights, error) {
+	return setUsageRights(g.client, g.id("/groups/%d/usage_rights"), rights, objs, opts)
+}
+
+// RemoveUsageRights will remove the usage rights of files and folders in the group.
+func (g *Group) RemoveUsageRights(objs []FileObj) error {
+	return removeUsageRights(g.client, g.id("/groups/%d/usage_rights"), objs)
+}
+
+// Licenses will list the licenses that can be used for the group's files.
+func (g *Group) Licenses() ([]License, error) {
+	return licenses(g.client, g.id("/groups/%d/content_licenses"))
+}
+
+// SetUsageRights will set the usage rights of the user's files and folders.
+func (u *User) SetUsageRights(rights UsageRights, objs []FileObj, opts ...Option) (*UsageRights, error) {
+	return setUsageRights(u.client, u.id("/users/%d/usage_rights"), rights, objs, opts)
+}
+
+// RemoveUsageRights will remove the usage rights of the user's files and folders.
+func (u *User) RemoveUsageRights(objs []FileObj) error {
+	return removeUsageRights(u.client, u.id("/users/%d/usage_rights"), objs)
+}
+
+// Licenses will list the licenses that can be used for the user's files.
+func (u *User) Licenses() ([]License, error) {
+	return licenses(u.client, u.id("/users/%d/content_licenses"))
+}
+
+func setUsageRights(d doer, path string, rights UsageRights, objs []FileObj, opts []Option) (*UsageRights, error) {
+	q, err := query.Values(&usageRightsOptions{rights})
+	if err != nil {
+		return nil, err
+	}
+	res := &UsageRights{}
+	for _, batch := range fileObjBatches(objs, usageRightsBatchSize) {
+		p := batch
+		for k, v := range q {
+			p[k] = v
+		}
+		p.Add(opts)
+		resp, err := put(d, path, p)
+		if err != nil {
+			return res, err
+		}
+		ur := UsageRights{}
+		err = json.NewDecoder(resp.Body).Decode(&ur)
+		resp.Body.Close()
+		if err != nil {
+			return res, err
+		}
+		ids := append(res.FileIDs, ur.FileIDs...)
+		*res = ur
+		res.FileIDs = ids
+	}
+	return res, nil
+}
+
+func removeUsageRights(d doer, path string, objs []FileObj) error {
+	for _, batch := range fileObjBatches(objs, usageRightsBatchSize) {
+		resp, err := delete(d, path, batch)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func licenses(d doer, path string) (l []License, err error) {
+	return l, getjson(d, &l, nil, path)
+}
+
+// fileObjBatches splits files and folders into the
+// file_ids[] and folder_ids[] parameters.
+func fileObjBatches(objs []FileObj, size int) []params {
+	var batches []params
+	for i := 0; i < len(objs); i += size {
+		end := i + size
+		if end > len(objs) {
+			end = len(objs)
+		}
+		p := params{}
+		for _, obj := range objs[i:end] {
+			id := strconv.Itoa(obj.GetID())
+			switch obj.Type() {
+			case TypeFile:
+				p["file_ids[]"] = append(p["file_ids[]"], id)
+			case TypeFolder:
+				p["folder_ids[]"] = append(p["folder_ids[]"], id)
+			}
+		}
+		batches = append(batches, p)
+	}
+	return batches
+}
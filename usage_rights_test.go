@@ -0,0 +1,74 @@
+package canvas
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestUsageRights(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	var requests int
+	mux.HandleFunc("/api/v1/courses/1/usage_rights", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.Method {
+		case "PUT":
+			requests++
+			is.Equal(q.Get("usage_rights[use_justification]"), JustificationCreativeCommons)
+			is.Equal(q.Get("usage_rights[license]"), "cc_by")
+			is.Equal(q.Get("usage_rights[legal_copyright]"), "")
+			is.Equal(q.Get("publish"), "true")
+			ids := q["file_ids[]"]
+			if requests == 1 {
+				is.Equal(len(ids), usageRightsBatchSize)
+				is.Equal(q["folder_ids[]"], []string(nil))
+			} else {
+				is.Equal(len(ids), 1)
+				is.Equal(q["folder_ids[]"], []string{"7"})
+			}
+			fmt.Fprintf(w, `{"use_justification":"creative_commons","license":"cc_by","license_name":"CC Attribution","file_ids":[%s]}`, ids[0])
+		case "DELETE":
+			is.Equal(q["file_ids[]"], []string{"1"})
+			is.Equal(q["folder_ids[]"], []string{"7"})
+			w.Write([]byte(`{"message":"2 files updated"}`))
+		}
+	})
+	mux.HandleFunc("/api/v1/courses/1/content_licenses", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"cc_by","name":"CC Attribution","url":"http://creativecommons.org/licenses/by/4.0"}]`))
+	})
+	mux.HandleFunc("/api/v1/files/1", func(w http.ResponseWriter, r *http.Request) {
+		is.Equal(r.URL.Query().Get("include[]"), "usage_rights")
+		w.Write([]byte(`{"id":1,"usage_rights":{"use_justification":"fair_use","legal_copyright":"(c) 2020"}}`))
+	})
+
+	course := &Course{ID: 1, client: client}
+	licenses, err := course.Licenses()
+	is.NoErr(err)
+	is.Equal(licenses[0].ID, "cc_by")
+
+	objs := make([]FileObj, 0, usageRightsBatchSize+2)
+	for i := 1; i <= usageRightsBatchSize+1; i++ {
+		objs = append(objs, &File{ID: i})
+	}
+	objs = append(objs, &Folder{ID: 7})
+	rights, err := course.SetUsageRights(
+		UsageRights{UseJustification: JustificationCreativeCommons, License: licenses[0].ID},
+		objs, Opt("publish", true),
+	)
+	is.NoErr(err)
+	is.Equal(requests, 2)
+	is.Equal(rights.LicenseName, "CC Attribution")
+	is.Equal(rights.FileIDs, []int{1, usageRightsBatchSize + 1})
+
+	is.NoErr(course.RemoveUsageRights([]FileObj{&File{ID: 1}, &Folder{ID: 7}}))
+
+	file := &File{}
+	is.NoErr(getjson(client, file, optEnc([]Option{IncludeOpt("usage_rights")}), "/files/1"))
+	is.Equal(file.UsageRights.UseJustification, JustificationFairUse)
+	is.Equal(file.UsageRights.LegalCopyright, "(c) 2020")
+}
@@ -10,23 +10,23 @@ import (
 
 // User is a canvas user
 type User struct {
-	ID              int          `json:"id"`
-	Name            string       `json:"name"`
-	Email           string       `json:"email"`
-	Bio             string       `json:"bio"`
-	SortableName    string       `json:"sortable_name"`
-	ShortName       string       `json:"short_name"`
-	SisUserID       string       `json:"sis_user_id"`
-	SisImportID     int          `json:"sis_import_id"`
-	IntegrationID   string       `json:"integration_id"`
-	CreatedAt       time.Time    `json:"created_at"`
-	LoginID         string       `json:"login_id"`
-	AvatarURL       string       `json:"avatar_url"`
-	Enrollments     []Enrollment `json:"enrollments"`
-	Locale          string       `json:"locale"`
-	EffectiveLocale string       `json:"effective_locale"`
-	LastLogin       time.Time    `json:"last_login"`
-	TimeZone        string       `json:"time_zone"`
+	ID              int           `json:"id"`
+	Name            string        `json:"name"`
+	Email           string        `json:"email"`
+	Bio             string        `json:"bio"`
+	SortableName    string        `json:"sortable_name"`
+	ShortName       string        `json:"short_name"`
+	SisUserID       string        `json:"sis_user_id"`
+	SisImportID     int           `json:"sis_import_id"`
+	IntegrationID   string        `json:"integration_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	LoginID         string        `json:"login_id"`
+	AvatarURL       string        `json:"avatar_url"`
+	Enrollments     []*Enrollment `json:"enrollments"`
+	Locale          string        `json:"locale"`
+	EffectiveLocale string        `json:"effective_locale"`
+	LastLogin       time.Time     `json:"last_login"`
+	TimeZone        string        `json:"time_zone"`
 
 	CanUpdateAvatar bool `json:"can_update_avatar"`
 	Permissions     struct {
@@ -37,6 +37,13 @@ type User struct {
 	client doer
 }
 
+func (u *User) setclient(d doer) {
+	u.client = d
+	for _, e := range u.Enrollments {
+		e.client = d
+	}
+}
+
 // Settings will get the user's settings.
 func (u *User) Settings() (settings map[string]interface{}, err error) {
 	// TODO: find the settings json response and use a struct not a map
@@ -45,12 +52,23 @@ func (u *User) Settings() (settings map[string]interface{}, err error) {
 
 // Courses will return the user's courses.
 func (u *User) Courses(opts ...Option) ([]*Course, error) {
-	return getCourses(u.client, u.id("/users/%d/courses"), optEnc(opts))
+	return getCourses(u.client, u.id("/users/%d/courses"), withInclude(opts, "favorites"))
 }
 
 // FavoriteCourses returns the user's list of favorites courses.
+//
+// Canvas only exposes favorites for the current user so this will
+// always return the favorites of the user that owns the api token.
 func (u *User) FavoriteCourses(opts ...Option) ([]*Course, error) {
-	return getCourses(u.client, "/users/favorites/courses", optEnc(opts))
+	return favoriteCourses(u.client, opts)
+}
+
+// FavoriteGroups returns the user's list of favorite groups.
+//
+// Canvas only exposes favorites for the current user so this will
+// always return the favorites of the user that owns the api token.
+func (u *User) FavoriteGroups(opts ...Option) ([]*Group, error) {
+	return listGroups(u.client, "/users/self/favorites/groups", opts)
 }
 
 // File will get a user's file by id
@@ -1,8 +1,14 @@
 package canvas
 
 import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 type params map[string][]string
@@ -33,6 +39,8 @@ func pathFromContextType(contextType string) string {
 		return "courses"
 	case "User":
 		return "users"
+	case "Group":
+		return "groups"
 	case "GroupCategory":
 		return "group_categories"
 	case "Account":
@@ -42,50 +50,127 @@ func pathFromContextType(contextType string) string {
 	}
 }
 
+// pathFromContextCode converts a context code (ex. "course_1234")
+// to an api path (ex. "courses/1234").
+func pathFromContextCode(code string) string {
+	i := strings.LastIndexByte(code, '_')
+	if i < 0 {
+		return ""
+	}
+	var ctx string
+	switch code[:i] {
+	case "course":
+		ctx = "courses"
+	case "group":
+		ctx = "groups"
+	case "user":
+		ctx = "users"
+	case "account":
+		ctx = "accounts"
+	default:
+		return ""
+	}
+	return ctx + "/" + code[i+1:]
+}
+
 var _ encoder = (*params)(nil)
 
+var contentTypes = struct {
+	sync.RWMutex
+	overrides map[string]string
+	builtin   map[string]string
+}{
+	overrides: map[string]string{},
+	// used before the system's mime types so that
+	// uploads get the same type on every platform
+	builtin: map[string]string{
+		".pdf":  "application/pdf",
+		".doc":  "application/msword",
+		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		".ppt":  "application/vnd.ms-powerpoint",
+		".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		".xls":  "application/vnd.ms-excel",
+		".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		".zip":  "application/zip",
+		".gz":   "application/gzip",
+		".json": "application/json",
+		".xml":  "application/xml",
+		".png":  "image/png",
+		".jpeg": "image/jpeg",
+		".jpg":  "image/jpeg",
+		".gif":  "image/gif",
+		".svg":  "image/svg+xml",
+		".html": "text/html",
+		".htm":  "text/html",
+		".cpp":  "text/x-c++src",
+		".hpp":  "text/x-c++src",
+		".txt":  "text/plain",
+	},
+}
+
+// RegisterContentType sets the content type used when uploading
+// files with the given extension, ex. RegisterContentType(".ipynb",
+// "application/x-ipynb+json"). Overrides take precedence over
+// every other way of finding a file's content type.
+func RegisterContentType(ext, contentType string) {
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	contentTypes.Lock()
+	contentTypes.overrides[strings.ToLower(ext)] = contentType
+	contentTypes.Unlock()
+}
+
+// filenameContentType finds the content type from a file's extension,
+// an empty string is returned when the extension is unknown.
 func filenameContentType(filename string) string {
-	ext := filepath.Ext(filename)
-	if ext[0] == '.' {
-		ext = ext[1:]
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return ""
+	}
+	contentTypes.RLock()
+	ct, ok := contentTypes.overrides[ext]
+	if !ok {
+		ct, ok = contentTypes.builtin[ext]
+	}
+	contentTypes.RUnlock()
+	if ok {
+		return ct
+	}
+	ct = mime.TypeByExtension(ext)
+	if mediatype, _, err := mime.ParseMediaType(ct); err == nil {
+		return mediatype
+	}
+	return ct
+}
+
+// sniffLen is the max number of bytes used by http.DetectContentType.
+const sniffLen = 512
+
+// detectContentType finds the content type of a file by its name and falls
+// back to looking at the start of the file. The returned reader must be
+// used in place of r since some of r may have been read.
+func detectContentType(filename string, r io.Reader) (string, io.Reader, error) {
+	if ct := filenameContentType(filename); ct != "" {
+		return ct, r, nil
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", r, err
+	}
+	head = head[:n]
+	r = io.MultiReader(bytes.NewReader(head), r)
+	if n == 0 {
+		return "", r, nil
+	}
+	ct := http.DetectContentType(head)
+	if ct == "application/octet-stream" {
+		// let canvas decide
+		return "", r, nil
 	}
-	switch ext {
-	case "pdf":
-		return "application/pdf"
-	case "doc":
-		return "application/msword"
-	case "docx":
-		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
-	case "ppt":
-		return "application/vnd.ms-powerpoint"
-	case "pptx":
-		return "application/vnd.openxmlformats-officedocument.presentationml.presentation"
-	case "xls":
-		return "application/vnd.ms-excel"
-	case "xlsx":
-		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
-	case "zip":
-		return "application/zip"
-	case "gz":
-		return "application/gzip"
-	case "json":
-		return "application/json"
-	case "xml":
-		return "application/xml"
-	case "png":
-		return "image/png"
-	case "jpeg", "jpg":
-		return "image/jpeg"
-	case "gif":
-		return "image/gif"
-	case "svg":
-		return "image/svg+xml"
-	case "html", "htm":
-		return "text/html"
-	case "cpp", "hpp":
-		return "text/x-c++src"
-	case "txt":
-		return "text/plain"
+	if mediatype, _, err := mime.ParseMediaType(ct); err == nil {
+		return mediatype, r, nil
 	}
-	return ""
+	return ct, r, nil
 }
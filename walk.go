@@ -0,0 +1,284 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SkipFolder is returned by a WalkFunc to skip the folder it was called
+// with. When returned for a file, the rest of the files and folders in the
+// file's folder are skipped.
+var SkipFolder = errors.New("skip this folder")
+
+// WalkFunc is called for every file and folder visited by Folder.Walk. The
+// path is slash separated and relative to the folder being walked, which
+// itself has the path ".". If listing a folder fails, the function is
+// called a second time for that folder with the error.
+type WalkFunc func(path string, obj FileObj, err error) error
+
+// WalkOrder is the order that a folder tree is walked in.
+type WalkOrder int
+
+const (
+	// DepthFirst visits everything in a folder before its next sibling.
+	DepthFirst WalkOrder = iota
+	// BreadthFirst visits every folder at one depth before going deeper.
+	BreadthFirst
+)
+
+// WalkOptions changes the behavior of Folder.Walk.
+type WalkOptions struct {
+	// Order defaults to DepthFirst.
+	Order WalkOrder
+	// Concurrency is the max number of folders listed at the same time.
+	// Folders are listed ahead of time so skipped folders may still be
+	// listed. Zero uses the same default as Mirror and Downloader.
+	Concurrency int
+}
+
+// Walk will visit the folder and everything below it calling fn for each
+// file and folder. Within a folder, files and folders are visited in
+// order of their names. The walk stops at the first error returned by fn
+// other than SkipFolder.
+func (f *Folder) Walk(fn WalkFunc, opts *WalkOptions) error {
+	if opts == nil {
+		opts = &WalkOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	w := &walker{fn: fn, sem: make(chan struct{}, concurrency)}
+	root := &walkNode{path: ".", obj: f, folder: f}
+	err := fn(root.path, f, nil)
+	if err != nil {
+		if err == SkipFolder {
+			return nil
+		}
+		return err
+	}
+	root.listing = w.list(f)
+
+	if opts.Order == BreadthFirst {
+		err = w.bfs(root)
+	} else {
+		err = w.dfs(root)
+	}
+	if err == SkipFolder {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	fn  WalkFunc
+	sem chan struct{}
+}
+
+type walkNode struct {
+	path string
+	obj  FileObj
+	// folder and listing are nil for files
+	folder  *Folder
+	listing *folderListing
+}
+
+type folderListing struct {
+	done    chan struct{}
+	entries []FileObj
+	err     error
+}
+
+// list starts listing a folder in the background.
+func (w *walker) list(f *Folder) *folderListing {
+	l := &folderListing{done: make(chan struct{})}
+	go func() {
+		defer close(l.done)
+		w.sem <- struct{}{}
+		defer func() { <-w.sem }()
+		folders, err := f.ListFolders()
+		if err != nil {
+			l.err = err
+			return
+		}
+		files, err := f.ListFiles()
+		if err != nil {
+			l.err = err
+			return
+		}
+		for _, sub := range folders {
+			sub.parent = f
+			l.entries = append(l.entries, sub)
+		}
+		for _, file := range files {
+			file.folder = f
+			l.entries = append(l.entries, file)
+		}
+		sort.SliceStable(l.entries, func(i, j int) bool {
+			return l.entries[i].Name() < l.entries[j].Name()
+		})
+	}()
+	return l
+}
+
+// children waits for a folder's listing and starts listing its
+// subfolders. The returned error is from the WalkFunc.
+func (w *walker) children(n *walkNode) ([]*walkNode, error) {
+	<-n.listing.done
+	if n.listing.err != nil {
+		err := w.fn(n.path, n.folder, n.listing.err)
+		if err == SkipFolder {
+			err = nil
+		}
+		return nil, err
+	}
+	nodes := make([]*walkNode, len(n.listing.entries))
+	for i, obj := range n.listing.entries {
+		nodes[i] = &walkNode{path: path.Join(n.path, obj.Name()), obj: obj}
+		if sub, ok := obj.(*Folder); ok {
+			nodes[i].folder = sub
+			nodes[i].listing = w.list(sub)
+		}
+	}
+	return nodes, nil
+}
+
+func (w *walker) dfs(n *walkNode) error {
+	children, err := w.children(n)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		err = w.fn(child.path, child.obj, nil)
+		if err == SkipFolder {
+			if child.folder == nil {
+				return nil // skip the rest of the parent folder
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if child.folder != nil {
+			if err = w.dfs(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) bfs(root *walkNode) error {
+	queue := []*walkNode{root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		children, err := w.children(n)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			err = w.fn(child.path, child.obj, nil)
+			if err == SkipFolder {
+				if child.folder == nil {
+					break
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+			if child.folder != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil
+}
+
+// HideAll will hide the folder and every file and folder below it.
+func (f *Folder) HideAll() error {
+	return f.editAll(Opt("hidden", true))
+}
+
+// UnhideAll will unhide the folder and every file and folder below it.
+func (f *Folder) UnhideAll() error {
+	return f.editAll(Opt("hidden", false))
+}
+
+// LockAll will lock the folder and every file and folder below it. If
+// lockAt is zero everything is locked right away and if unlockAt is not
+// zero everything will be unlocked at that time.
+func (f *Folder) LockAll(lockAt, unlockAt time.Time) error {
+	opts := []Option{Opt("locked", lockAt.IsZero())}
+	if !lockAt.IsZero() {
+		opts = append(opts, DateOpt("lock_at", lockAt))
+	}
+	if !unlockAt.IsZero() {
+		opts = append(opts, DateOpt("unlock_at", unlockAt))
+	}
+	return f.editAll(opts...)
+}
+
+// UnlockAll will unlock the folder and every file and folder below it.
+func (f *Folder) UnlockAll() error {
+	return f.editAll(Opt("locked", false), Opt("lock_at", ""), Opt("unlock_at", ""))
+}
+
+// DeleteAll will delete the folder along with every file and
+// folder in it. Use Delete to only delete empty folders.
+func (f *Folder) DeleteAll() error {
+	return f.Delete(Opt("force", true))
+}
+
+// editAll walks the folder and updates everything concurrently.
+func (f *Folder) editAll(opts ...Option) error {
+	var objs []FileObj
+	err := f.Walk(func(_ string, obj FileObj, err error) error {
+		if err != nil {
+			return err
+		}
+		objs = append(objs, obj)
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, defaultConcurrency)
+		failed int
+		first  error
+	)
+	for _, obj := range objs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(obj FileObj) {
+			defer func() { <-sem; wg.Done() }()
+			var err error
+			switch o := obj.(type) {
+			case *Folder:
+				err = o.edit(opts...)
+			case *File:
+				err = o.edit(opts...)
+			}
+			if err != nil {
+				mu.Lock()
+				failed++
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}(obj)
+	}
+	wg.Wait()
+	if first != nil {
+		return fmt.Errorf("%d of %d updates failed: %w", failed, len(objs), first)
+	}
+	return nil
+}
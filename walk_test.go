@@ -0,0 +1,89 @@
+package canvas
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFolder_Walk(t *testing.T) {
+	is := is.New(t)
+	client, mux, server := testServer()
+	defer server.Close()
+
+	folders := map[int]string{
+		1: `[{"id":2,"name":"sub"},{"id":3,"name":"zz"}]`,
+		2: `[{"id":4,"name":"deep"}]`,
+	}
+	files := map[int]string{
+		1: `[{"id":10,"display_name":"a.txt"}]`,
+		2: `[{"id":11,"display_name":"b.txt"}]`,
+		3: `[{"id":12,"display_name":"d.txt"}]`,
+		4: `[{"id":13,"display_name":"c.txt"}]`,
+	}
+	var (
+		mu    sync.Mutex
+		edits []string
+	)
+	for id := 1; id <= 4; id++ {
+		id := id
+		mux.HandleFunc(fmt.Sprintf("/api/v1/folders/%d/folders", id), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", testLinkHeader)
+			if list, ok := folders[id]; ok {
+				w.Write([]byte(list))
+				return
+			}
+			w.Write([]byte(`[]`))
+		})
+		mux.HandleFunc(fmt.Sprintf("/api/v1/folders/%d/files", id), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", testLinkHeader)
+			w.Write([]byte(files[id]))
+		})
+	}
+	edit := func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "PUT")
+		is.Equal(r.URL.Query().Get("hidden"), "true")
+		mu.Lock()
+		edits = append(edits, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}
+	mux.HandleFunc("/api/v1/folders/", edit)
+	mux.HandleFunc("/api/v1/files/", edit)
+
+	root := &Folder{ID: 1, Foldername: "root", client: client}
+	walk := func(opts *WalkOptions, skip string) []string {
+		var paths []string
+		err := root.Walk(func(p string, obj FileObj, err error) error {
+			is.NoErr(err)
+			paths = append(paths, p)
+			if p == skip {
+				return SkipFolder
+			}
+			return nil
+		}, opts)
+		is.NoErr(err)
+		return paths
+	}
+	is.Equal(walk(nil, ""), []string{
+		".", "a.txt", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt", "zz", "zz/d.txt",
+	})
+	is.Equal(walk(&WalkOptions{Order: BreadthFirst, Concurrency: 1}, ""), []string{
+		".", "a.txt", "sub", "zz", "sub/b.txt", "sub/deep", "zz/d.txt", "sub/deep/c.txt",
+	})
+	is.Equal(walk(nil, "sub"), []string{".", "a.txt", "sub", "zz", "zz/d.txt"})
+	is.Equal(walk(nil, "a.txt"), []string{".", "a.txt"})
+	is.Equal(walk(nil, "."), []string{"."})
+
+	is.NoErr(root.HideAll())
+	sort.Strings(edits)
+	is.Equal(strings.Join(edits, " "), strings.Join([]string{
+		"/api/v1/files/10", "/api/v1/files/11", "/api/v1/files/12", "/api/v1/files/13",
+		"/api/v1/folders/1", "/api/v1/folders/2", "/api/v1/folders/3", "/api/v1/folders/4",
+	}, " "))
+}